@@ -27,6 +27,23 @@ type KeyMap interface {
 	FullHelp() [][]key.Binding
 }
 
+// Section is a titled group of keybindings. See SectionedKeyMap.
+type Section struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+// SectionedKeyMap is a KeyMap that organizes its full help into titled
+// sections, such as "Navigation" and "Editing". When passed to View the full
+// help is rendered with SectionedHelpView rather than FullHelpView.
+type SectionedKeyMap interface {
+	KeyMap
+
+	// FullHelpSections returns the sections to be displayed in the full
+	// help. Sections are rendered in the order in which they're returned.
+	FullHelpSections() []Section
+}
+
 // Styles is a set of available style definitions for the Help bubble.
 type Styles struct {
 	Ellipsis lipgloss.Style
@@ -40,6 +57,9 @@ type Styles struct {
 	FullKey       lipgloss.Style
 	FullDesc      lipgloss.Style
 	FullSeparator lipgloss.Style
+
+	// Styling for section titles in sectioned full help
+	SectionTitle lipgloss.Style
 }
 
 // Model contains the state of the help view.
@@ -86,6 +106,7 @@ func New() Model {
 			FullKey:        keyStyle.Copy(),
 			FullDesc:       descStyle.Copy(),
 			FullSeparator:  sepStyle.Copy(),
+			SectionTitle:   keyStyle.Copy().Bold(true),
 		},
 	}
 }
@@ -103,6 +124,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 // View renders the help view's current state.
 func (m Model) View(k KeyMap) string {
 	if m.ShowAll {
+		if s, ok := k.(SectionedKeyMap); ok {
+			return m.SectionedHelpView(s.FullHelpSections())
+		}
 		return m.FullHelpView(k.FullHelp())
 	}
 	return m.ShortHelpView(k.ShortHelp())
@@ -223,6 +247,73 @@ func (m Model) FullHelpView(groups [][]key.Binding) string {
 	return lipgloss.JoinHorizontal(lipgloss.Top, out...)
 }
 
+// SectionedHelpView renders help from a slice of titled sections. Each section
+// renders into a column with its title above its bindings. Sections wrap onto
+// additional rows when they don't fit in the available width.
+func (m Model) SectionedHelpView(sections []Section) string {
+	if len(sections) == 0 {
+		return ""
+	}
+
+	var (
+		rows []string
+		row  []string
+
+		rowWidth int
+		sep      = m.Styles.FullSeparator.Render(m.FullSeparator)
+		sepWidth = lipgloss.Width(sep)
+	)
+
+	for _, section := range sections {
+		if !shouldRenderColumn(section.Bindings) {
+			continue
+		}
+
+		var (
+			keys         []string
+			descriptions []string
+		)
+
+		for _, kb := range section.Bindings {
+			if !kb.Enabled() {
+				continue
+			}
+			keys = append(keys, kb.Help().Key)
+			descriptions = append(descriptions, kb.Help().Desc)
+		}
+
+		col := lipgloss.JoinVertical(lipgloss.Left,
+			m.Styles.SectionTitle.Render(section.Title),
+			lipgloss.JoinHorizontal(lipgloss.Top,
+				m.Styles.FullKey.Render(strings.Join(keys, "\n")),
+				m.Styles.FullKey.Render(" "),
+				m.Styles.FullDesc.Render(strings.Join(descriptions, "\n")),
+			),
+		)
+		colWidth := lipgloss.Width(col)
+
+		// Wrap onto a new row if this section won't fit.
+		if len(row) > 0 && m.Width > 0 && rowWidth+sepWidth+colWidth > m.Width {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			row = nil
+			rowWidth = 0
+		}
+
+		if len(row) > 0 {
+			row = append(row, sep)
+			rowWidth += sepWidth
+		}
+		row = append(row, col)
+		rowWidth += colWidth
+	}
+
+	if len(row) > 0 {
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	}
+
+	return strings.Join(rows, "\n\n")
+}
+
 func shouldRenderColumn(b []key.Binding) (ok bool) {
 	for _, v := range b {
 		if v.Enabled() {
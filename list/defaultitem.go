@@ -19,8 +19,18 @@ type DefaultItemStyles struct {
 	NormalDesc  lipgloss.Style
 
 	// The selected item state.
-	SelectedTitle lipgloss.Style
-	SelectedDesc  lipgloss.Style
+	SelectedTitle  lipgloss.Style
+	SelectedDesc   lipgloss.Style
+	SelectedPrefix lipgloss.Style
+
+	// The selected item while it's being dragged, which marks where it'll be
+	// dropped. See Model.MouseDragEnabled.
+	DraggedTitle lipgloss.Style
+	DraggedDesc  lipgloss.Style
+
+	// The active item state. See Model.SetActiveIndex.
+	ActiveTitle lipgloss.Style
+	ActiveDesc  lipgloss.Style
 
 	// The dimmed state, for when the filter input is initially activated.
 	DimmedTitle lipgloss.Style
@@ -28,6 +38,12 @@ type DefaultItemStyles struct {
 
 	// Charcters matching the current filter, if any.
 	FilterMatch lipgloss.Style
+
+	// The accessory of an AccessoryItem.
+	Accessory lipgloss.Style
+
+	// The checkmark of multi-selected items. See Model.SetSelected.
+	Checkmark lipgloss.Style
 }
 
 // NewDefaultItemStyles returns style definitions for a default item. See
@@ -49,6 +65,22 @@ func NewDefaultItemStyles() (s DefaultItemStyles) {
 	s.SelectedDesc = s.SelectedTitle.Copy().
 		Foreground(lipgloss.AdaptiveColor{Light: "#F793FF", Dark: "#AD58B4"})
 
+	s.DraggedTitle = s.SelectedTitle.Copy().
+		Border(lipgloss.ThickBorder(), false, false, false, true)
+
+	s.DraggedDesc = s.SelectedDesc.Copy().
+		Border(lipgloss.ThickBorder(), false, false, false, true)
+
+	s.SelectedPrefix = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#EE6FF8", Dark: "#EE6FF8"})
+
+	s.ActiveTitle = s.NormalTitle.Copy().
+		Foreground(lipgloss.AdaptiveColor{Light: "#02A66A", Dark: "#04B575"}).
+		Bold(true)
+
+	s.ActiveDesc = s.NormalDesc.Copy().
+		Foreground(lipgloss.AdaptiveColor{Light: "#5EC49A", Dark: "#3C8B6B"})
+
 	s.DimmedTitle = lipgloss.NewStyle().
 		Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}).
 		Padding(0, 0, 0, 2)
@@ -58,6 +90,12 @@ func NewDefaultItemStyles() (s DefaultItemStyles) {
 
 	s.FilterMatch = lipgloss.NewStyle().Underline(true)
 
+	s.Accessory = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+
+	s.Checkmark = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#02A66A", Dark: "#04B575"})
+
 	return s
 }
 
@@ -68,12 +106,29 @@ type DefaultItem interface {
 	Description() string
 }
 
+// AccessoryItem is a DefaultItem with an accessory, such as a badge, count or
+// timestamp, which DefaultDelegate renders flush right on the title line.
+type AccessoryItem interface {
+	DefaultItem
+	Accessory() string
+}
+
 // DefaultDelegate is a standard delegate designed to work in lists. It's
 // styled by DefaultItemStyles, which can be customized as you like.
 //
 // The description line can be hidden by setting Description to false, which
-// renders the list as single-line-items. The spacing between items can be set
-// with the SetSpacing method.
+// renders the list as single-line-items. Alternatively, setting Compact renders
+// the description on the same line as the title. The spacing between items can
+// be set with the SetSpacing method.
+//
+// While multi-selection is enabled, multi-selected items are marked with a
+// checkmark, styled with the Checkmark style, and the other items are indented
+// to match.
+//
+// SelectedPrefix and UnselectedPrefix, if set, are rendered before the title of
+// selected and unselected items respectively, such as "▸ " and "  ". To use
+// them in place of the default bar, remove the border from the SelectedTitle
+// and SelectedDesc styles.
 //
 // Setting UpdateFunc is optional. If it's set it will be called when the
 // ItemDelegate called, which is called when the list's Update function is
@@ -82,13 +137,16 @@ type DefaultItem interface {
 // Settings ShortHelpFunc and FullHelpFunc is optional. They can can be set to
 // include items in the list's default short and full help menus.
 type DefaultDelegate struct {
-	ShowDescription bool
-	Styles          DefaultItemStyles
-	UpdateFunc      func(tea.Msg, *Model) tea.Cmd
-	ShortHelpFunc   func() []key.Binding
-	FullHelpFunc    func() [][]key.Binding
-	height          int
-	spacing         int
+	ShowDescription  bool
+	Compact          bool
+	SelectedPrefix   string
+	UnselectedPrefix string
+	Styles           DefaultItemStyles
+	UpdateFunc       func(tea.Msg, *Model) tea.Cmd
+	ShortHelpFunc    func() []key.Binding
+	FullHelpFunc     func() [][]key.Binding
+	height           int
+	spacing          int
 }
 
 // NewDefaultDelegate creates a new delegate with default styles.
@@ -107,18 +165,19 @@ func (d *DefaultDelegate) SetHeight(i int) {
 }
 
 // Height returns the delegate's preferred height.
-// This has effect only if ShowDescription is true,
+// This has effect only if ShowDescription is true and Compact is false,
 // otherwise height is always 1.
 func (d DefaultDelegate) Height() int {
-	if d.ShowDescription {
+	if d.ShowDescription && !d.Compact {
 		return d.height
 	}
 	return 1
 }
 
-// SetSpacing set the delegate's spacing.
+// SetSpacing sets the number of blank lines between items. Negative values are
+// treated as 0.
 func (d *DefaultDelegate) SetSpacing(i int) {
-	d.spacing = i
+	d.spacing = max(0, i)
 }
 
 // Spacing returns the delegate's spacing.
@@ -137,9 +196,9 @@ func (d DefaultDelegate) Update(msg tea.Msg, m *Model) tea.Cmd {
 // Render prints an item.
 func (d DefaultDelegate) Render(w io.Writer, m Model, index int, item Item) {
 	var (
-		title, desc  string
-		matchedRunes []int
-		s            = &d.Styles
+		title, desc, acc string
+		matchedRunes     []int
+		s                = &d.Styles
 	)
 
 	if i, ok := item.(DefaultItem); ok {
@@ -148,6 +207,9 @@ func (d DefaultDelegate) Render(w io.Writer, m Model, index int, item Item) {
 	} else {
 		return
 	}
+	if i, ok := item.(AccessoryItem); ok {
+		acc = i.Accessory()
+	}
 
 	if m.width <= 0 {
 		// short-circuit
@@ -155,9 +217,30 @@ func (d DefaultDelegate) Render(w io.Writer, m Model, index int, item Item) {
 	}
 
 	// Prevent text from exceeding list width
-	textwidth := uint(m.width - s.NormalTitle.GetPaddingLeft() - s.NormalTitle.GetPaddingRight())
-	title = truncate.StringWithTail(title, textwidth, ellipsis)
-	if d.ShowDescription {
+	prefixWidth := max(lipgloss.Width(d.SelectedPrefix), lipgloss.Width(d.UnselectedPrefix))
+	var checkWidth int
+	if m.MultiSelectEnabled() {
+		checkWidth = lipgloss.Width(checkmark) + 1
+	}
+	textwidth := uint(m.width - s.NormalTitle.GetPaddingLeft() - s.NormalTitle.GetPaddingRight() - prefixWidth - checkWidth)
+
+	// Leave room for the accessory, if any, on the title line.
+	titlewidth := textwidth
+	if acc != "" {
+		titlewidth = uint(max(0, int(textwidth)-lipgloss.Width(acc)-1))
+	}
+
+	title = truncate.StringWithTail(title, titlewidth, ellipsis)
+	if d.ShowDescription && d.Compact {
+		// Fit the first line of the description after the title.
+		desc = strings.SplitN(desc, "\n", 2)[0]
+		descWidth := int(titlewidth) - lipgloss.Width(title) - 1
+		if descWidth > 0 {
+			desc = truncate.StringWithTail(desc, uint(descWidth), ellipsis)
+		} else {
+			desc = ""
+		}
+	} else if d.ShowDescription {
 		var lines []string
 		for i, line := range strings.Split(desc, "\n") {
 			if i >= d.height-1 {
@@ -180,29 +263,93 @@ func (d DefaultDelegate) Render(w io.Writer, m Model, index int, item Item) {
 		matchedRunes = m.MatchesForItem(index)
 	}
 
+	// The active item is styled differently unless it's also selected.
+	normalTitle, normalDesc := s.NormalTitle, s.NormalDesc
+	if m.IsActive(index) {
+		normalTitle, normalDesc = s.ActiveTitle, s.ActiveDesc
+	}
+
+	// While the selected item is dragged, its position is where it'll be
+	// dropped, so it's marked with the dragged styles.
+	selectedTitle, selectedDesc := s.SelectedTitle, s.SelectedDesc
+	if m.Dragging() {
+		selectedTitle, selectedDesc = s.DraggedTitle, s.DraggedDesc
+	}
+
+	// In compact mode the description follows the title, so it's rendered
+	// without the padding and borders.
+	dimmedDesc := s.DimmedDesc
+	if d.Compact {
+		dimmedDesc = dimmedDesc.Inline(true)
+		selectedDesc = selectedDesc.Inline(true)
+		normalDesc = normalDesc.Inline(true)
+	}
+
 	if emptyFilter {
 		title = s.DimmedTitle.Render(title)
-		desc = s.DimmedDesc.Render(desc)
+		desc = dimmedDesc.Render(desc)
 	} else if isSelected && m.FilterState() != Filtering {
 		if isFiltered {
 			// Highlight matches
-			unmatched := s.SelectedTitle.Inline(true)
+			unmatched := selectedTitle.Inline(true)
 			matched := unmatched.Copy().Inherit(s.FilterMatch)
 			title = lipgloss.StyleRunes(title, matchedRunes, matched, unmatched)
 		}
-		title = s.SelectedTitle.Render(title)
-		desc = s.SelectedDesc.Render(desc)
+		title = selectedTitle.Render(title)
+		desc = selectedDesc.Render(desc)
 	} else {
 		if isFiltered {
 			// Highlight matches
-			unmatched := s.NormalTitle.Inline(true)
+			unmatched := normalTitle.Inline(true)
 			matched := unmatched.Copy().Inherit(s.FilterMatch)
 			title = lipgloss.StyleRunes(title, matchedRunes, matched, unmatched)
 		}
-		title = s.NormalTitle.Render(title)
-		desc = s.NormalDesc.Render(desc)
+		title = normalTitle.Render(title)
+		desc = normalDesc.Render(desc)
+	}
+
+	if prefixWidth > 0 {
+		prefix := d.UnselectedPrefix
+		if isSelected && m.FilterState() != Filtering && !emptyFilter {
+			prefix = s.SelectedPrefix.Render(d.SelectedPrefix)
+		}
+		title = prefix + strings.Repeat(" ", prefixWidth-lipgloss.Width(prefix)) + title
+
+		// Keep the description aligned with the title.
+		if !d.Compact {
+			indent := strings.Repeat(" ", prefixWidth)
+			desc = indent + strings.ReplaceAll(desc, "\n", "\n"+indent)
+		}
 	}
 
+	// Multi-selected items are marked with a checkmark, and the rest are
+	// indented to match.
+	if checkWidth > 0 {
+		mark := strings.Repeat(" ", checkWidth)
+		if m.IsSelected(index) {
+			mark = s.Checkmark.Render(checkmark) + " "
+		}
+		title = mark + title
+
+		if !d.Compact {
+			indent := strings.Repeat(" ", checkWidth)
+			desc = indent + strings.ReplaceAll(desc, "\n", "\n"+indent)
+		}
+	}
+
+	if d.ShowDescription && d.Compact && lipgloss.Width(desc) > 0 {
+		title = fmt.Sprintf("%s %s", title, desc)
+	}
+
+	if acc != "" {
+		gap := max(1, m.width-lipgloss.Width(title)-lipgloss.Width(acc))
+		title += strings.Repeat(" ", gap) + s.Accessory.Render(acc)
+	}
+
+	if d.ShowDescription && d.Compact {
+		fmt.Fprintf(w, "%s", title)
+		return
+	}
 	if d.ShowDescription {
 		fmt.Fprintf(w, "%s\n%s", title, desc)
 		return
@@ -4,6 +4,13 @@ import "github.com/charmbracelet/bubbles/key"
 
 // KeyMap defines keybindings. It satisfies to the help.KeyMap interface, which
 // is used to render the menu menu.
+//
+// Any binding can be remapped to avoid conflicts with the rest of your
+// application. For example, to start filtering with "s" instead of "/":
+//
+//     l.KeyMap.Filter.SetKeys("s")
+//     l.KeyMap.Filter.SetHelp("s", "filter")
+//
 type KeyMap struct {
 	// Keybindings used when browsing the list.
 	CursorUp    key.Binding
@@ -15,6 +22,23 @@ type KeyMap struct {
 	Filter      key.Binding
 	ClearFilter key.Binding
 
+	// Activate activates the selected item, sending an ItemActivatedMsg. It
+	// accepts any number of keys, so you could, for example, also activate
+	// with "right" and "l" (just remember to remove them from NextPage). It's
+	// not shown in the help view by default.
+	Activate key.Binding
+
+	// RemoveItem removes the selected item, sending an ItemRemovedMsg. It's
+	// not bound to any keys by default; to remove items with "x":
+	//
+	//     l.KeyMap.RemoveItem.SetKeys("x")
+	//
+	RemoveItem key.Binding
+
+	// Peek shows or hides the details of the selected item. It's only
+	// enabled once a function to render them is set with Model.SetPeekFunc.
+	Peek key.Binding
+
 	// Keybindings used when setting a filter.
 	CancelWhileFiltering key.Binding
 	AcceptWhileFiltering key.Binding
@@ -43,12 +67,12 @@ func DefaultKeyMap() KeyMap {
 			key.WithHelp("↓/j", "down"),
 		),
 		PrevPage: key.NewBinding(
-			key.WithKeys("left", "h", "pgup", "b", "u"),
-			key.WithHelp("←/h/pgup", "prev page"),
+			key.WithKeys("left", "h", "pgup", "ctrl+b", "b", "u"),
+			key.WithHelp("←/h/pgup/ctrl+b", "prev page"),
 		),
 		NextPage: key.NewBinding(
-			key.WithKeys("right", "l", "pgdown", "f", "d"),
-			key.WithHelp("→/l/pgdn", "next page"),
+			key.WithKeys("right", "l", "pgdown", "ctrl+f", "f", "d"),
+			key.WithHelp("→/l/pgdn/ctrl+f", "next page"),
 		),
 		GoToStart: key.NewBinding(
 			key.WithKeys("home", "g"),
@@ -66,6 +90,17 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "clear filter"),
 		),
+		Activate: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "select"),
+		),
+		RemoveItem: key.NewBinding(
+			key.WithHelp("x", "remove"),
+		),
+		Peek: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "peek"),
+		),
 
 		// Filtering.
 		CancelWhileFiltering: key.NewBinding(
@@ -6,6 +6,7 @@ package list
 import (
 	"fmt"
 	"io"
+	"reflect"
 	"sort"
 	"strings"
 	"time"
@@ -29,6 +30,32 @@ type Item interface {
 	FilterValue() string
 }
 
+// ActivatableItem is an item with its own behavior when it's activated with
+// the Activate keybinding, such as opening a submenu in a command palette. The
+// command returned by Activate is run along with the ItemActivatedMsg that's
+// sent for every activated item, so apps can either let items handle their
+// activation or dispatch on the item's type in one place.
+type ActivatableItem interface {
+	Item
+
+	// Activate returns the command to run when the item is activated.
+	Activate() tea.Cmd
+}
+
+// GroupItem is an item that heads a collapsible group. The items following a
+// GroupItem, up to the next GroupItem, are considered its children and are
+// hidden while the group is collapsed. Activating a GroupItem toggles whether
+// or not it's collapsed.
+//
+// Groups only apply when the list is unfiltered; filtering searches through
+// all items regardless of whether their group is collapsed.
+type GroupItem interface {
+	Item
+
+	// GroupID uniquely identifies the group.
+	GroupID() string
+}
+
 // ItemDelegate encapsulates the general functionality for all list items. The
 // benefit to separating this logic from the item itself is that you can change
 // the functionality of items without changing the actual items themselves.
@@ -42,19 +69,28 @@ type ItemDelegate interface {
 	// Height is the height of the list item.
 	Height() int
 
-	// Spacing is the size of the horizontal gap between list items in cells.
+	// Spacing is the size of the vertical gap between list items in lines.
+	// It's taken into account when calculating how many items fit on a page.
 	Spacing() int
 
 	// Update is the update loop for items. All messages in the list's update
 	// loop will pass through here except when the user is setting a filter.
 	// Use this method to perform item-level updates appropriate to this
 	// delegate.
+	//
+	// The list's state is available through the given model, such as the
+	// selection with SelectedItem and Index, and the filter with FilterState,
+	// FilterValue and SelectedItemMatches. Use ItemIndex to find the index of
+	// the selected item in the list's items, which methods such as SetItem
+	// and RemoveItem take.
 	Update(msg tea.Msg, m *Model) tea.Cmd
 }
 
 type filteredItem struct {
+	index   int   // index of the item in the list's items
 	item    Item  // item matched
 	matches []int // rune indices of matched items
+	pinned  bool  // whether index refers to the pinned items instead
 }
 
 type filteredItems []filteredItem
@@ -82,23 +118,63 @@ type Rank struct {
 	Index int
 	// Indices of the actual word that were matched against the filter term.
 	MatchedIndexes []int
+	// The score of the match, where higher is better. Filters that don't
+	// score matches may leave this at zero.
+	Score int
 }
 
 // DefaultFilter uses the sahilm/fuzzy to filter through the list.
-// This is set by default.
+// This is set by default. Matches with equal scores keep their original order.
 func DefaultFilter(term string, targets []string) []Rank {
 	var ranks = fuzzy.Find(term, targets)
-	sort.Stable(ranks)
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].Score != ranks[j].Score {
+			return ranks[i].Score > ranks[j].Score
+		}
+		return ranks[i].Index < ranks[j].Index
+	})
 	result := make([]Rank, len(ranks))
 	for i, r := range ranks {
 		result[i] = Rank{
 			Index:          r.Index,
 			MatchedIndexes: r.MatchedIndexes,
+			Score:          r.Score,
 		}
 	}
 	return result
 }
 
+// ItemRemovedMsg is sent when an item is removed with the RemoveItem
+// keybinding.
+type ItemRemovedMsg struct {
+	// Index is the index the item had in the list's items.
+	Index int
+
+	// Item is the removed item.
+	Item Item
+}
+
+// ItemActivatedMsg is sent when an item is activated with the Activate
+// keybinding. Item is the activated item as it was given to the list, so apps
+// can switch on its type to decide what to do. See also ActivatableItem.
+type ItemActivatedMsg struct {
+	// Index is the index of the activated item in the list's items, or -1
+	// if the item is pinned. See Items and SetPinnedItems.
+	Index int
+
+	// Item is the activated item.
+	Item Item
+}
+
+// SelectionChangedMsg is sent by Update when the selected item changes, be it
+// from navigation, filtering or a call to Select. Index is the index of the
+// selected item in the list's items, or -1 if the item is pinned or nothing is
+// selected, in which case Item is nil.
+type SelectionChangedMsg struct {
+	Index int
+	Item  Item
+}
+
 type statusMessageTimeoutMsg struct{}
 
 // FilterState describes the current filtering state on the model.
@@ -129,9 +205,24 @@ type Model struct {
 	showHelp         bool
 	filteringEnabled bool
 
+	statusBarInTitle bool
+
+	clearFilterOnActivate bool
+
 	itemNameSingular string
 	itemNamePlural   string
 
+	moreIndicator func(above, below int) string
+	titleFunc     func(m Model) string
+	helpFunc      func(m Model) string
+
+	filterTiebreak func(a, b Item) bool
+
+	// Renders the details shown below the selected item. See SetPeekFunc.
+	peekFunc   func(Item) string
+	peekHeight int
+	peeking    bool
+
 	Title  string
 	Styles Styles
 
@@ -141,6 +232,19 @@ type Model struct {
 	// Filter is used to filter the list.
 	Filter FilterFunc
 
+	// RankFunc, if set, is called with the ranks returned by Filter before
+	// they're displayed, allowing results to be re-sorted, such as to boost
+	// recently used items. By default ranks are displayed as returned.
+	RankFunc func(ranks []Rank) []Rank
+
+	// Equal, if set, reports whether two items are the same item. It's used
+	// to keep the selected item selected when the items are replaced with
+	// SetItems, and to tell whether the selection has changed. By default,
+	// items of comparable types are compared with ==, and other items, such
+	// as structs containing slices, by value with reflect.DeepEqual. Neither
+	// works for items whose values change.
+	Equal func(a, b Item) bool
+
 	disableQuitKeybindings bool
 
 	// Additional key mappings for the short and full help views. This allows
@@ -151,6 +255,19 @@ type Model struct {
 	AdditionalShortHelpKeys func() []key.Binding
 	AdditionalFullHelpKeys  func() []key.Binding
 
+	// MouseDragEnabled, if true, lets items be reordered by dragging them with
+	// the mouse while the list is unfiltered. Mouse support must be enabled
+	// in the program, and YPosition must be set for mouse events to be mapped
+	// to items. Items can't be dropped into collapsed groups.
+	MouseDragEnabled bool
+
+	// YPosition is the row of the terminal at which the list is rendered.
+	// It's used to map mouse events to items.
+	YPosition int
+
+	// Whether the selected item is being dragged with the mouse.
+	dragging bool
+
 	spinner     spinner.Model
 	showSpinner bool
 	width       int
@@ -158,6 +275,10 @@ type Model struct {
 	Paginator   paginator.Model
 	cursor      int
 	Help        help.Model
+
+	// FilterInput is the text input used to edit the filter. It's a regular
+	// textinput.Model, so it supports cursor movement, word deletion and
+	// pasting, and can be styled like any other text input.
 	FilterInput textinput.Model
 	filterState FilterState
 
@@ -171,11 +292,38 @@ type Model struct {
 	// The master set of items we're working with.
 	items []Item
 
+	// Items shown above the rest of the items. See SetPinnedItems.
+	pinned []Item
+
+	// IDs of collapsed groups. See GroupItem.
+	collapsed map[string]bool
+
+	// The items shown while the list is unfiltered, if they differ from items
+	// because there are pinned items or collapsed groups. See updateVisible.
+	visible      filteredItems
+	visibleItems []Item
+
 	// Filtered items we're currently displaying. Filtering, toggles and so on
 	// will alter this slice so we can show what is relevant. For that reason,
 	// this field should be considered ephemeral.
 	filteredItems filteredItems
 
+	// The selection as of the last update, used to detect when it changes.
+	selected      Item
+	selectedIndex int
+
+	// An item to select once filtering completes. See SetItems.
+	reselect Item
+
+	// Index of the active item in items, or -1. See SetActiveIndex.
+	activeIndex int
+
+	// Indices in items of the items in the multi-selection. See SetSelected.
+	// The map is shared by copies of the model, so it's replaced rather than
+	// modified.
+	multiSelected map[int]bool
+	multiSelect   bool
+
 	delegate ItemDelegate
 }
 
@@ -222,10 +370,13 @@ func New(items []Item, delegate ItemDelegate, width, height int) Model {
 		Paginator: p,
 		spinner:   sp,
 		Help:      help.NewModel(),
+
+		activeIndex: -1,
 	}
 
 	m.updatePagination()
 	m.updateKeybindings()
+	m.selected, m.selectedIndex = m.selection()
 	return m
 }
 
@@ -234,6 +385,25 @@ func New(items []Item, delegate ItemDelegate, width, height int) Model {
 // Deprecated. Use New instead.
 var NewModel = New
 
+// SetFilterPrompt sets the prompt shown before the filter input, which is
+// "Filter: " by default.
+func (m *Model) SetFilterPrompt(prompt string) {
+	m.FilterInput.Prompt = prompt
+	m.setSize(m.width, m.height)
+}
+
+// SetStyles sets the list's styles, applying the filter prompt and cursor
+// styles to FilterInput and the spinner style to the spinner. Setting Styles
+// directly doesn't update those. For further styling, FilterInput can be
+// configured like any other text input.
+func (m *Model) SetStyles(s Styles) {
+	m.Styles = s
+	m.FilterInput.PromptStyle = s.FilterPrompt
+	m.FilterInput.CursorStyle = s.FilterCursor
+	m.spinner.Style = s.Spinner
+	m.setSize(m.width, m.height)
+}
+
 // SetFilteringEnabled enables or disables filtering. Note that this is different
 // from ShowFilter, which merely hides or shows the input view.
 func (m *Model) SetFilteringEnabled(v bool) {
@@ -249,6 +419,79 @@ func (m Model) FilteringEnabled() bool {
 	return m.filteringEnabled
 }
 
+// SetClearFilterOnActivate sets whether or not activating an item with the
+// Activate keybinding clears an applied filter. The activated item remains
+// selected after the filter is cleared. By default the filter is kept, which
+// allows several items to be activated from a single search.
+func (m *Model) SetClearFilterOnActivate(v bool) {
+	m.clearFilterOnActivate = v
+}
+
+// ClearFilterOnActivate returns whether or not activating an item clears an
+// applied filter.
+func (m Model) ClearFilterOnActivate() bool {
+	return m.clearFilterOnActivate
+}
+
+// SetTitleFunc sets a function that renders the title from the list's state,
+// such as "Inbox (3 unread)", in place of Title. It's called whenever the title
+// is rendered. Like Title, it should return a single line. Pass nil to use
+// Title again.
+func (m *Model) SetTitleFunc(fn func(m Model) string) {
+	m.titleFunc = fn
+	m.updatePagination()
+}
+
+// SetHelpFunc sets a function that renders the help view in place of the
+// built-in help. Its output isn't styled with HelpStyle, and its height is
+// taken into account when paginating. Pass nil to use the built-in help again.
+func (m *Model) SetHelpFunc(fn func(m Model) string) {
+	m.helpFunc = fn
+	m.updatePagination()
+}
+
+// SetFilterTiebreak sets a function that orders filtered items with equal
+// scores, reporting whether a should come before b, such as to break ties
+// alphabetically. By default they keep their original order. Note that with
+// a Filter that doesn't score matches, all matches are ordered by it. Pass nil
+// to remove it.
+func (m *Model) SetFilterTiebreak(fn func(a, b Item) bool) {
+	m.filterTiebreak = fn
+}
+
+// SetPeekFunc sets a function that renders details about an item. When the
+// Peek keybinding is pressed, the details of the selected item are shown
+// below it, following the selection as it moves, until it's pressed again.
+// Height is the number of lines reserved for the details, which are truncated
+// to fit. Pass nil to disable peeking.
+func (m *Model) SetPeekFunc(fn func(Item) string, height int) {
+	m.peekFunc = fn
+	m.peekHeight = max(0, height)
+	if fn == nil {
+		m.peeking = false
+	}
+	m.updatePagination()
+	m.updateKeybindings()
+}
+
+// SetPeeking shows or hides the details of the selected item. It has no
+// effect unless a function was set with SetPeekFunc.
+func (m *Model) SetPeeking(v bool) {
+	m.peeking = v
+	m.updatePagination()
+}
+
+// Peeking returns whether the details of the selected item are shown.
+func (m Model) Peeking() bool {
+	return m.peekOpen()
+}
+
+// peekOpen returns whether room is made below the selected item for its
+// details.
+func (m Model) peekOpen() bool {
+	return m.peeking && m.peekFunc != nil && m.peekHeight > 0
+}
+
 // SetShowTitle shows or hides the title bar.
 func (m *Model) SetShowTitle(v bool) {
 	m.showTitle = v
@@ -291,6 +534,32 @@ func (m Model) ShowStatusBar() bool {
 	return m.showStatusBar
 }
 
+// SetStatusBarInTitle sets whether or not the status bar is rendered on the
+// same line as the title, aligned to the right, to save space. It's rendered
+// on its own line as usual if the title bar is hidden, and left out if there
+// isn't room for it next to the title.
+func (m *Model) SetStatusBarInTitle(v bool) {
+	m.statusBarInTitle = v
+	m.updatePagination()
+}
+
+// StatusBarInTitle returns whether or not the status bar is set to be rendered
+// on the same line as the title.
+func (m Model) StatusBarInTitle() bool {
+	return m.statusBarInTitle
+}
+
+// showTitleBar returns whether or not the title bar is rendered.
+func (m Model) showTitleBar() bool {
+	return m.showTitle || (m.showFilter && m.filteringEnabled)
+}
+
+// statusInTitle returns whether or not the status bar is rendered in the
+// title bar rather than on its own line.
+func (m Model) statusInTitle() bool {
+	return m.statusBarInTitle && m.showStatusBar && m.showTitleBar()
+}
+
 // SetStatusBarItemName defines a replacement for the items identifier.
 // Defaults to item/items.
 func (m *Model) SetStatusBarItemName(singular, plural string) {
@@ -303,6 +572,31 @@ func (m Model) StatusBarItemName() (string, string) {
 	return m.itemNameSingular, m.itemNamePlural
 }
 
+// SetMoreIndicator replaces the paginator with an indicator of how many items
+// are off-screen above and below the current page, rendered by the given
+// function and styled with Styles.PaginationStyle. Pass nil to restore the
+// paginator. For example:
+//
+//     m.SetMoreIndicator(list.DefaultMoreIndicator)
+//
+func (m *Model) SetMoreIndicator(fn func(above, below int) string) {
+	m.moreIndicator = fn
+	m.updatePagination()
+}
+
+// DefaultMoreIndicator renders the number of items above and below the
+// current page, such as "↑ 10 more  ↓ 5 more". See SetMoreIndicator.
+func DefaultMoreIndicator(above, below int) string {
+	var parts []string
+	if above > 0 {
+		parts = append(parts, fmt.Sprintf("↑ %d more", above))
+	}
+	if below > 0 {
+		parts = append(parts, fmt.Sprintf("↓ %d more", below))
+	}
+	return strings.Join(parts, "  ")
+}
+
 // SetShowPagination hides or shoes the paginator. Note that pagination will
 // still be active, it simply won't be displayed.
 func (m *Model) SetShowPagination(v bool) {
@@ -331,10 +625,76 @@ func (m Model) Items() []Item {
 	return m.items
 }
 
-// Set the items available in the list. This returns a command.
+// Set the items available in the list. If the selected item is among the new
+// items it stays selected; see Equal. This returns a command.
 func (m *Model) SetItems(i []Item) tea.Cmd {
 	var cmd tea.Cmd
+	selected := m.SelectedItem()
 	m.items = i
+	m.updateVisible()
+	if m.activeIndex >= len(i) {
+		m.activeIndex = -1
+	}
+	m.remapSelected(func(j int) int {
+		if j >= len(i) {
+			return -1
+		}
+		return j
+	})
+
+	if m.filterState != Unfiltered {
+		m.filteredItems = nil
+		m.reselect = selected
+		cmd = filterItems(*m)
+	}
+
+	m.updatePagination()
+	m.updateKeybindings()
+	if m.filterState == Unfiltered {
+		m.selectItem(selected)
+	}
+	return cmd
+}
+
+// selectItem selects the given item if it's visible.
+func (m *Model) selectItem(item Item) {
+	if item == nil {
+		return
+	}
+	for i, v := range m.VisibleItems() {
+		if m.sameItem(v, item) {
+			m.Select(i)
+			return
+		}
+	}
+}
+
+// sameItem reports whether two items are the same item. See Equal.
+func (m Model) sameItem(a, b Item) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if m.Equal != nil {
+		return m.Equal(a, b)
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if !reflect.TypeOf(a).Comparable() {
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
+
+// SetPinnedItems sets items to show above the rest of the items, such as
+// recently used items in a command palette. Pinned items stay at the top when
+// filtering and are filtered separately from the rest of the items. They're
+// not part of Items, so the same item can be both pinned and in the list.
+// Use IsPinned to render pinned items differently. This returns a command.
+func (m *Model) SetPinnedItems(i []Item) tea.Cmd {
+	var cmd tea.Cmd
+	m.pinned = i
+	m.updateVisible()
 
 	if m.filterState != Unfiltered {
 		m.filteredItems = nil
@@ -346,6 +706,135 @@ func (m *Model) SetItems(i []Item) tea.Cmd {
 	return cmd
 }
 
+// PinnedItems returns the pinned items. See SetPinnedItems.
+func (m Model) PinnedItems() []Item {
+	return m.pinned
+}
+
+// IsPinned returns whether the item at the given index of the visible items is
+// a pinned item.
+func (m Model) IsPinned(index int) bool {
+	if len(m.pinned) == 0 {
+		return false
+	}
+	items := m.filteredItems
+	if m.filterState == Unfiltered {
+		items = m.unfilteredItems()
+	}
+	return index >= 0 && index < len(items) && items[index].pinned
+}
+
+// SetActiveIndex marks the item at the given index in the list's items as the
+// active item, such as the item that's currently playing in a queue. Unlike
+// the selection, the active item doesn't change when navigating or filtering,
+// and it's rendered with its own style by DefaultDelegate. Pass -1 to clear
+// the active item.
+func (m *Model) SetActiveIndex(index int) {
+	if index < 0 || index >= len(m.items) {
+		index = -1
+	}
+	m.activeIndex = index
+}
+
+// ActiveIndex returns the index of the active item in the list's items, or -1
+// if there is none. See SetActiveIndex.
+func (m Model) ActiveIndex() int {
+	return m.activeIndex
+}
+
+// IsActive returns whether the item at the given index of the visible items is
+// the active item. See SetActiveIndex.
+func (m Model) IsActive(index int) bool {
+	return m.activeIndex >= 0 && m.itemIndex(index) == m.activeIndex
+}
+
+// SetSelected adds or removes the item at the given index of the visible items
+// to or from the multi-selection, which is separate from the selected item
+// that the cursor is on. Multi-selected items are rendered with a checkmark by
+// DefaultDelegate. Pinned items can't be multi-selected. Selecting an item
+// enables multi-selection; see SetMultiSelectEnabled.
+func (m *Model) SetSelected(index int, v bool) {
+	i := m.itemIndex(index)
+	if i < 0 || m.multiSelected[i] == v {
+		return
+	}
+
+	selected := make(map[int]bool, len(m.multiSelected)+1)
+	for j := range m.multiSelected {
+		selected[j] = true
+	}
+	if v {
+		selected[i] = true
+		m.multiSelect = true
+	} else {
+		delete(selected, i)
+	}
+	m.multiSelected = selected
+}
+
+// SetMultiSelectEnabled sets whether the list is used with a multi-selection,
+// which tells delegates to make room for marking selected items even while
+// nothing is selected, so that items don't shift when the first one is.
+// Disabling it clears the multi-selection.
+func (m *Model) SetMultiSelectEnabled(v bool) {
+	m.multiSelect = v
+	if !v {
+		m.ClearSelection()
+	}
+}
+
+// MultiSelectEnabled returns whether the list is used with a multi-selection.
+// See SetMultiSelectEnabled.
+func (m Model) MultiSelectEnabled() bool {
+	return m.multiSelect
+}
+
+// ToggleSelected adds or removes the item at the given index of the visible
+// items to or from the multi-selection. See SetSelected.
+func (m *Model) ToggleSelected(index int) {
+	m.SetSelected(index, !m.IsSelected(index))
+}
+
+// ClearSelection empties the multi-selection. See SetSelected.
+func (m *Model) ClearSelection() {
+	m.multiSelected = nil
+}
+
+// IsSelected returns whether the item at the given index of the visible items
+// is in the multi-selection. See SetSelected.
+func (m Model) IsSelected(index int) bool {
+	i := m.itemIndex(index)
+	return i >= 0 && m.multiSelected[i]
+}
+
+// SelectedItems returns the items in the multi-selection, in the order they
+// appear in the list's items. See SetSelected.
+func (m Model) SelectedItems() []Item {
+	var items []Item
+	for i, item := range m.items {
+		if m.multiSelected[i] {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// remapSelected updates the indices of the multi-selected items after the
+// items have changed. The given function returns the new index of an item,
+// or -1 if it's been removed.
+func (m *Model) remapSelected(f func(int) int) {
+	if len(m.multiSelected) == 0 {
+		return
+	}
+	selected := make(map[int]bool, len(m.multiSelected))
+	for i := range m.multiSelected {
+		if j := f(i); j >= 0 {
+			selected[j] = true
+		}
+	}
+	m.multiSelected = selected
+}
+
 // Select selects the given index of the list and goes to its respective page.
 func (m *Model) Select(index int) {
 	m.Paginator.Page = index / m.Paginator.PerPage
@@ -363,9 +852,14 @@ func (m *Model) ResetFilter() {
 }
 
 // Replace an item at the given index. This returns a command.
+//
+// The index is an index into Items, which isn't the same as Index while the
+// list is filtered, has pinned items or has collapsed groups. To replace the
+// selected item, use ItemIndex(Index()), which is -1 for pinned items.
 func (m *Model) SetItem(index int, item Item) tea.Cmd {
 	var cmd tea.Cmd
 	m.items[index] = item
+	m.updateVisible()
 
 	if m.filterState != Unfiltered {
 		cmd = filterItems(*m)
@@ -377,9 +871,21 @@ func (m *Model) SetItem(index int, item Item) tea.Cmd {
 
 // Insert an item at the given index. If index is out of the upper bound, the
 // item will be appended. This returns a command.
+//
+// Like SetItem, the index is an index into Items, not the visible items.
 func (m *Model) InsertItem(index int, item Item) tea.Cmd {
 	var cmd tea.Cmd
 	m.items = insertItemIntoSlice(m.items, item, index)
+	m.updateVisible()
+	if m.activeIndex >= 0 && index <= m.activeIndex {
+		m.activeIndex++
+	}
+	m.remapSelected(func(i int) int {
+		if i >= index {
+			return i + 1
+		}
+		return i
+	})
 
 	if m.filterState != Unfiltered {
 		cmd = filterItems(*m)
@@ -393,8 +899,31 @@ func (m *Model) InsertItem(index int, item Item) tea.Cmd {
 // RemoveItem removes an item at the given index. If the index is out of bounds
 // this will be a no-op. O(n) complexity, which probably won't matter in the
 // case of a TUI.
+//
+// Like SetItem, the index is an index into Items, not the visible items, so
+// to remove the selected item, use RemoveItem(ItemIndex(Index())).
 func (m *Model) RemoveItem(index int) {
+	if index < 0 || index >= len(m.items) {
+		return
+	}
+
+	switch {
+	case index == m.activeIndex:
+		m.activeIndex = -1
+	case index < m.activeIndex:
+		m.activeIndex--
+	}
+	m.remapSelected(func(i int) int {
+		switch {
+		case i == index:
+			return -1
+		case i > index:
+			return i - 1
+		}
+		return i
+	})
 	m.items = removeItemFromSlice(m.items, index)
+	m.updateVisible()
 	if m.filterState != Unfiltered {
 		m.filteredItems = removeFilterMatchFromSlice(m.filteredItems, index)
 		if len(m.filteredItems) == 0 {
@@ -402,6 +931,50 @@ func (m *Model) RemoveItem(index int) {
 		}
 	}
 	m.updatePagination()
+	m.updateKeybindings()
+	m.clampCursor()
+}
+
+// MoveItem moves the item at the given index to another index, shifting the
+// items in between. If either index is out of bounds this will be a no-op.
+// The cursor stays where it is. This returns a command.
+func (m *Model) MoveItem(from, to int) tea.Cmd {
+	if from < 0 || from >= len(m.items) || to < 0 || to >= len(m.items) || from == to {
+		return nil
+	}
+
+	item := m.items[from]
+	m.items = removeItemFromSlice(m.items, from)
+	m.items = insertItemIntoSlice(m.items, item, to)
+	m.updateVisible()
+
+	switch {
+	case m.activeIndex == from:
+		m.activeIndex = to
+	case from < m.activeIndex && m.activeIndex <= to:
+		m.activeIndex--
+	case to <= m.activeIndex && m.activeIndex < from:
+		m.activeIndex++
+	}
+	m.remapSelected(func(i int) int {
+		switch {
+		case i == from:
+			return to
+		case from < i && i <= to:
+			return i - 1
+		case to <= i && i < from:
+			return i + 1
+		}
+		return i
+	})
+
+	var cmd tea.Cmd
+	if m.filterState != Unfiltered {
+		cmd = filterItems(*m)
+	}
+
+	m.updatePagination()
+	return cmd
 }
 
 // Set the item delegate.
@@ -415,9 +988,75 @@ func (m Model) VisibleItems() []Item {
 	if m.filterState != Unfiltered {
 		return m.filteredItems.items()
 	}
+	if m.visibleItems != nil {
+		return m.visibleItems
+	}
 	return m.items
 }
 
+// ToggleGroup collapses the group with the given ID if it's expanded and
+// expands it if it's collapsed. See GroupItem.
+func (m *Model) ToggleGroup(id string) {
+	m.SetGroupCollapsed(id, !m.GroupCollapsed(id))
+}
+
+// SetGroupCollapsed collapses or expands the group with the given ID. See
+// GroupItem. If the selected item is hidden by collapsing its group, the
+// group's header is selected instead.
+func (m *Model) SetGroupCollapsed(id string, collapsed bool) {
+	if m.collapsed[id] == collapsed {
+		return
+	}
+
+	var selected filteredItem
+	keepSelection := false
+	if m.filterState == Unfiltered {
+		if items := m.unfilteredItems(); m.Index() < len(items) {
+			selected = items[m.Index()]
+			keepSelection = true
+		}
+	}
+
+	// Copy the collapsed groups so that copies of the model aren't affected.
+	groups := make(map[string]bool, len(m.collapsed)+1)
+	for g := range m.collapsed {
+		groups[g] = true
+	}
+	if collapsed {
+		groups[id] = true
+	} else {
+		delete(groups, id)
+	}
+	m.collapsed = groups
+	m.updateVisible()
+	m.updatePagination()
+	if keepSelection {
+		m.selectUnfiltered(selected)
+	}
+	m.updateKeybindings()
+}
+
+// selectUnfiltered selects the given item in the unfiltered list. If it's
+// hidden in a collapsed group, the closest visible item above it, which is the
+// group's header, is selected instead.
+func (m *Model) selectUnfiltered(fi filteredItem) {
+	for i, v := range m.unfilteredItems() {
+		if v.pinned != fi.pinned {
+			continue
+		}
+		if v.index > fi.index {
+			break
+		}
+		m.Select(i)
+	}
+}
+
+// GroupCollapsed returns whether or not the group with the given ID is
+// collapsed.
+func (m Model) GroupCollapsed(id string) bool {
+	return m.collapsed[id]
+}
+
 // SelectedItems returns the current selected item in the list.
 func (m Model) SelectedItem() Item {
 	i := m.Index()
@@ -441,8 +1080,19 @@ func (m Model) MatchesForItem(index int) []int {
 	return m.filteredItems[index].matches
 }
 
-// Index returns the index of the currently selected item as it appears in the
-// entire slice of items.
+// SelectedItemMatches returns the indices of the runes in the selected item's
+// FilterValue that match the filter, such as to highlight the matched portion
+// when acting on the item. It returns nil if no filter is set.
+func (m Model) SelectedItemMatches() []int {
+	if m.filterState == Unfiltered {
+		return nil
+	}
+	return m.MatchesForItem(m.Index())
+}
+
+// Index returns the index of the currently selected item among the visible
+// items. While the list is filtered, has pinned items or has collapsed groups,
+// that's not its index in Items; use ItemIndex to get that.
 func (m Model) Index() int {
 	return m.Paginator.Page*m.Paginator.PerPage + m.cursor
 }
@@ -503,14 +1153,54 @@ func (m *Model) CursorDown() {
 	m.cursor = itemsOnPage - 1
 }
 
-// PrevPage moves to the previous page, if available.
-func (m Model) PrevPage() {
+// GoToStart moves the cursor to the first item in the list.
+func (m *Model) GoToStart() {
+	m.Paginator.Page = 0
+	m.cursor = 0
+}
+
+// GoToEnd moves the cursor to the last item in the list.
+func (m *Model) GoToEnd() {
+	m.Paginator.Page = max(0, m.Paginator.TotalPages-1)
+	m.cursor = max(0, m.Paginator.ItemsOnPage(len(m.VisibleItems()))-1)
+}
+
+// PrevPage moves to the previous page, if available. The cursor keeps its
+// position on the page.
+func (m *Model) PrevPage() {
 	m.Paginator.PrevPage()
+	m.clampCursor()
 }
 
-// NextPage moves to the next page, if available.
-func (m Model) NextPage() {
+// NextPage moves to the next page, if available. The cursor keeps its position
+// on the page, moving up if the page has fewer items.
+func (m *Model) NextPage() {
 	m.Paginator.NextPage()
+	m.clampCursor()
+}
+
+// Page returns the index of the page being shown, starting at 0.
+func (m Model) Page() int {
+	return m.Paginator.Page
+}
+
+// SetPage shows the page with the given index, starting at 0, keeping it within
+// bounds. The cursor keeps its position on the page, moving up if the page has
+// fewer items.
+func (m *Model) SetPage(page int) {
+	m.Paginator.Page = max(0, min(page, m.Paginator.TotalPages-1))
+	m.clampCursor()
+}
+
+// TotalPages returns the number of pages.
+func (m Model) TotalPages() int {
+	return m.Paginator.TotalPages
+}
+
+// clampCursor keeps the cursor on an item on the current page.
+func (m *Model) clampCursor() {
+	itemsOnPage := m.Paginator.ItemsOnPage(len(m.VisibleItems()))
+	m.cursor = max(0, min(m.cursor, itemsOnPage-1))
 }
 
 // FilterState returns the current filter state.
@@ -632,11 +1322,94 @@ func (m *Model) resetFiltering() {
 }
 
 func (m Model) itemsAsFilterItems() filteredItems {
-	fi := make([]filteredItem, len(m.items))
+	fi := make([]filteredItem, 0, len(m.pinned)+len(m.items))
+	for i, item := range m.pinned {
+		fi = append(fi, filteredItem{
+			index:  i,
+			item:   item,
+			pinned: true,
+		})
+	}
+	for i, item := range m.items {
+		fi = append(fi, filteredItem{
+			index: i,
+			item:  item,
+		})
+	}
+	return filteredItems(fi)
+}
+
+// ItemIndex returns the index in the list's items of the item at the given
+// index in the visible items, such as Index, or -1 if it's pinned or out of
+// range. The two differ while the list is filtered, has pinned items, or has
+// collapsed groups.
+func (m Model) ItemIndex(visibleIndex int) int {
+	if visibleIndex < 0 || visibleIndex >= len(m.VisibleItems()) {
+		return -1
+	}
+	return m.itemIndex(visibleIndex)
+}
+
+// itemIndex returns the index in the list's items of the item at the given
+// index in the visible items, or -1 if it's pinned.
+func (m Model) itemIndex(visibleIndex int) int {
+	var items filteredItems
+	switch {
+	case m.filterState != Unfiltered:
+		items = m.filteredItems
+	case m.visible != nil:
+		items = m.visible
+	default:
+		return visibleIndex
+	}
+	if visibleIndex < 0 || visibleIndex >= len(items) || items[visibleIndex].pinned {
+		return -1
+	}
+	return items[visibleIndex].index
+}
+
+// updateVisible caches the items shown while the list is unfiltered. It must be
+// called whenever the items, the pinned items or the collapsed groups change.
+func (m *Model) updateVisible() {
+	if len(m.pinned) == 0 && len(m.collapsed) == 0 {
+		m.visible, m.visibleItems = nil, nil
+		return
+	}
+	m.visible = m.buildUnfilteredItems()
+	m.visibleItems = m.visible.items()
+}
+
+// unfilteredItems returns the items shown when the list is unfiltered: the
+// pinned items followed by the list's items, excluding the children of
+// collapsed groups.
+func (m Model) unfilteredItems() filteredItems {
+	if m.visible != nil {
+		return m.visible
+	}
+	return m.buildUnfilteredItems()
+}
+
+// buildUnfilteredItems builds the items returned by unfilteredItems.
+func (m Model) buildUnfilteredItems() filteredItems {
+	fi := make([]filteredItem, 0, len(m.pinned)+len(m.items))
+	for i, item := range m.pinned {
+		fi = append(fi, filteredItem{
+			index:  i,
+			item:   item,
+			pinned: true,
+		})
+	}
+	hidden := false
 	for i, item := range m.items {
-		fi[i] = filteredItem{
-			item: item,
+		if g, ok := item.(GroupItem); ok {
+			hidden = m.collapsed[g.GroupID()]
+		} else if hidden {
+			continue
 		}
+		fi = append(fi, filteredItem{
+			index: i,
+			item:  item,
+		})
 	}
 	return filteredItems(fi)
 }
@@ -653,6 +1426,9 @@ func (m *Model) updateKeybindings() {
 		m.KeyMap.GoToEnd.SetEnabled(false)
 		m.KeyMap.Filter.SetEnabled(false)
 		m.KeyMap.ClearFilter.SetEnabled(false)
+		m.KeyMap.Activate.SetEnabled(false)
+		m.KeyMap.RemoveItem.SetEnabled(false)
+		m.KeyMap.Peek.SetEnabled(false)
 		m.KeyMap.CancelWhileFiltering.SetEnabled(true)
 		m.KeyMap.AcceptWhileFiltering.SetEnabled(m.FilterInput.Value() != "")
 		m.KeyMap.Quit.SetEnabled(false)
@@ -670,6 +1446,9 @@ func (m *Model) updateKeybindings() {
 
 		m.KeyMap.GoToStart.SetEnabled(hasItems)
 		m.KeyMap.GoToEnd.SetEnabled(hasItems)
+		m.KeyMap.Activate.SetEnabled(hasItems)
+		m.KeyMap.RemoveItem.SetEnabled(hasItems)
+		m.KeyMap.Peek.SetEnabled(hasItems && m.peekFunc != nil)
 
 		m.KeyMap.Filter.SetEnabled(m.filteringEnabled && hasItems)
 		m.KeyMap.ClearFilter.SetEnabled(m.filterState == FilterApplied)
@@ -693,10 +1472,10 @@ func (m *Model) updatePagination() {
 	index := m.Index()
 	availHeight := m.height
 
-	if m.showTitle || (m.showFilter && m.filteringEnabled) {
+	if m.showTitleBar() {
 		availHeight -= lipgloss.Height(m.titleView())
 	}
-	if m.showStatusBar {
+	if m.showStatusBar && !m.statusInTitle() {
 		availHeight -= lipgloss.Height(m.statusView())
 	}
 	if m.showPagination {
@@ -705,6 +1484,9 @@ func (m *Model) updatePagination() {
 	if m.showHelp {
 		availHeight -= lipgloss.Height(m.helpView())
 	}
+	if m.peekOpen() {
+		availHeight -= m.peekHeight
+	}
 
 	m.Paginator.PerPage = max(1, availHeight/(m.delegate.Height()+m.delegate.Spacing()))
 
@@ -743,7 +1525,12 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	case FilterMatchesMsg:
 		m.filteredItems = filteredItems(msg)
-		return m, nil
+		m.updatePagination()
+		if m.reselect != nil {
+			m.selectItem(m.reselect)
+			m.reselect = nil
+		}
+		return m, m.selectionChanged()
 
 	case spinner.TickMsg:
 		newSpinnerModel, cmd := m.spinner.Update(msg)
@@ -761,16 +1548,121 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	} else {
 		cmds = append(cmds, m.handleBrowsing(msg))
 	}
+	cmds = append(cmds, m.selectionChanged())
 
 	return m, tea.Batch(cmds...)
 }
 
+// handleDrag reorders items as they're dragged with the mouse. Terminals don't
+// distinguish between clicking and dragging, so the first left button event
+// picks up the item under the mouse and the following ones move it, until the
+// button is released.
+func (m *Model) handleDrag(msg tea.MouseMsg) tea.Cmd {
+	if !m.MouseDragEnabled || m.filterState != Unfiltered {
+		m.dragging = false
+		return nil
+	}
+
+	switch msg.Type {
+	case tea.MouseLeft:
+		i := m.itemAt(msg.Y)
+		if i < 0 || m.itemIndex(i) < 0 {
+			return nil
+		}
+		if !m.dragging {
+			m.Select(i)
+			m.dragging = true
+			return nil
+		}
+		from, to := m.itemIndex(m.Index()), m.itemIndex(i)
+		if from < 0 || from == to {
+			return nil
+		}
+		// Moving an item down onto the header of a collapsed group would put
+		// it among the group's hidden children, so it's kept where it is.
+		if g, ok := m.items[to].(GroupItem); ok && from < to && m.collapsed[g.GroupID()] {
+			return nil
+		}
+		cmd := m.MoveItem(from, to)
+		m.Select(i)
+		return cmd
+
+	case tea.MouseRelease:
+		m.dragging = false
+	}
+	return nil
+}
+
+// Dragging returns whether the selected item is being dragged with the mouse.
+// Since the item moves as it's dragged, its position is where it'll be
+// dropped; delegates can use this to mark it.
+func (m Model) Dragging() bool {
+	return m.dragging
+}
+
+// itemAt returns the index, among the visible items, of the item rendered at
+// the given row of the terminal, or -1 if there's no item there.
+func (m Model) itemAt(y int) int {
+	row := y - m.YPosition
+	if m.showTitleBar() {
+		row -= lipgloss.Height(m.titleView())
+	}
+	if m.showStatusBar && !m.statusInTitle() {
+		row -= lipgloss.Height(m.statusView())
+	}
+
+	stride := m.delegate.Height() + m.delegate.Spacing()
+	if row < 0 || stride <= 0 {
+		return -1
+	}
+
+	// Skip over the details below the selected item.
+	if m.peekOpen() {
+		if details := m.cursor*stride + m.delegate.Height(); row >= details {
+			if row < details+m.peekHeight {
+				return -1
+			}
+			row -= m.peekHeight
+		}
+	}
+	slot := row / stride
+	if slot >= m.Paginator.ItemsOnPage(len(m.VisibleItems())) {
+		return -1
+	}
+	return m.Paginator.Page*m.Paginator.PerPage + slot
+}
+
+// selection returns the selected item and its index in the list's items, or
+// nil and -1 if nothing is selected.
+func (m Model) selection() (Item, int) {
+	item := m.SelectedItem()
+	if item == nil {
+		return nil, -1
+	}
+	return item, m.itemIndex(m.Index())
+}
+
+// selectionChanged returns a command to send a SelectionChangedMsg if the
+// selection has changed since it was last checked.
+func (m *Model) selectionChanged() tea.Cmd {
+	item, index := m.selection()
+	if index == m.selectedIndex && m.sameItem(item, m.selected) {
+		return nil
+	}
+	m.selected, m.selectedIndex = item, index
+	return func() tea.Msg {
+		return SelectionChangedMsg{Index: index, Item: item}
+	}
+}
+
 // Updates for when a user is browsing the list.
 func (m *Model) handleBrowsing(msg tea.Msg) tea.Cmd {
 	var cmds []tea.Cmd
-	numItems := len(m.VisibleItems())
 
 	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		cmds = append(cmds, m.handleDrag(msg))
+
 	case tea.KeyMsg:
 		switch {
 		// Note: we match clear filter before quit because, by default, they're
@@ -788,18 +1680,25 @@ func (m *Model) handleBrowsing(msg tea.Msg) tea.Cmd {
 			m.CursorDown()
 
 		case key.Matches(msg, m.KeyMap.PrevPage):
-			m.Paginator.PrevPage()
+			m.PrevPage()
 
 		case key.Matches(msg, m.KeyMap.NextPage):
-			m.Paginator.NextPage()
+			m.NextPage()
 
 		case key.Matches(msg, m.KeyMap.GoToStart):
-			m.Paginator.Page = 0
-			m.cursor = 0
+			m.GoToStart()
 
 		case key.Matches(msg, m.KeyMap.GoToEnd):
-			m.Paginator.Page = m.Paginator.TotalPages - 1
-			m.cursor = m.Paginator.ItemsOnPage(numItems) - 1
+			m.GoToEnd()
+
+		case key.Matches(msg, m.KeyMap.Activate):
+			cmds = append(cmds, m.activate())
+
+		case key.Matches(msg, m.KeyMap.RemoveItem):
+			cmds = append(cmds, m.removeSelected())
+
+		case key.Matches(msg, m.KeyMap.Peek):
+			m.SetPeeking(!m.peeking)
 
 		case key.Matches(msg, m.KeyMap.Filter):
 			m.hideStatusMessage()
@@ -835,6 +1734,55 @@ func (m *Model) handleBrowsing(msg tea.Msg) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+// removeSelected removes the selected item, returning a command to send an
+// ItemRemovedMsg. Pinned items can't be removed.
+func (m *Model) removeSelected() tea.Cmd {
+	index := m.itemIndex(m.Index())
+	if index < 0 || index >= len(m.items) {
+		return nil
+	}
+	item := m.items[index]
+	m.RemoveItem(index)
+	return func() tea.Msg {
+		return ItemRemovedMsg{Index: index, Item: item}
+	}
+}
+
+// activate handles the activation of the selected item, returning a command
+// to send an ItemActivatedMsg.
+func (m *Model) activate() tea.Cmd {
+	item := m.SelectedItem()
+	if item == nil {
+		return nil
+	}
+
+	if g, ok := item.(GroupItem); ok && m.filterState == Unfiltered {
+		m.ToggleGroup(g.GroupID())
+		return nil
+	}
+
+	index := m.itemIndex(m.Index())
+	if m.clearFilterOnActivate && m.filterState == FilterApplied {
+		// Keep the activated item selected once the filter is cleared.
+		fi := m.filteredItems[m.Index()]
+		m.resetFiltering()
+		for i, v := range m.unfilteredItems() {
+			if v.pinned == fi.pinned && v.index == fi.index {
+				m.Select(i)
+				break
+			}
+		}
+	}
+
+	cmd := func() tea.Msg {
+		return ItemActivatedMsg{Index: index, Item: item}
+	}
+	if a, ok := item.(ActivatableItem); ok {
+		return tea.Batch(cmd, a.Activate())
+	}
+	return cmd
+}
+
 // Updates for when a user is in the filter editing interface.
 func (m *Model) handleFiltering(msg tea.Msg) tea.Cmd {
 	var cmds []tea.Cmd
@@ -948,11 +1896,21 @@ func (m Model) FullHelp() [][]key.Binding {
 	}
 
 	listLevelBindings := []key.Binding{
+		m.KeyMap.Peek,
+	}
+
+	// RemoveItem isn't bound to any keys by default, so only show it once it
+	// is.
+	if len(m.KeyMap.RemoveItem.Keys()) > 0 {
+		listLevelBindings = append(listLevelBindings, m.KeyMap.RemoveItem)
+	}
+
+	listLevelBindings = append(listLevelBindings,
 		m.KeyMap.Filter,
 		m.KeyMap.ClearFilter,
 		m.KeyMap.AcceptWhileFiltering,
 		m.KeyMap.CancelWhileFiltering,
-	}
+	)
 
 	if !filtering && m.AdditionalFullHelpKeys != nil {
 		listLevelBindings = append(listLevelBindings, m.AdditionalFullHelpKeys()...)
@@ -973,13 +1931,13 @@ func (m Model) View() string {
 		availHeight = m.height
 	)
 
-	if m.showTitle || (m.showFilter && m.filteringEnabled) {
+	if m.showTitleBar() {
 		v := m.titleView()
 		sections = append(sections, v)
 		availHeight -= lipgloss.Height(v)
 	}
 
-	if m.showStatusBar {
+	if m.showStatusBar && !m.statusInTitle() {
 		v := m.statusView()
 		sections = append(sections, v)
 		availHeight -= lipgloss.Height(v)
@@ -1034,7 +1992,11 @@ func (m Model) titleView() string {
 			titleBarStyle = titleBarStyle.PaddingLeft(titleBarGap - spinnerWidth - lipgloss.Width(spinnerLeftGap))
 		}
 
-		view += m.Styles.Title.Render(m.Title)
+		title := m.Title
+		if m.titleFunc != nil {
+			title = m.titleFunc(m)
+		}
+		view += m.Styles.Title.Render(title)
 
 		// Status message
 		if m.filterState != Filtering {
@@ -1043,6 +2005,19 @@ func (m Model) titleView() string {
 		}
 	}
 
+	// Status bar, aligned to the right
+	if m.statusInTitle() {
+		status := m.Styles.StatusBar.Copy().Inline(true).Render(m.statusText())
+		reserved := 0
+		if m.showSpinner && !spinnerOnLeft {
+			reserved = spinnerWidth + 1
+		}
+		gap := m.width - lipgloss.Width(m.Styles.TitleBar.Render(view)) - lipgloss.Width(status) - reserved
+		if gap > 0 {
+			view += strings.Repeat(" ", gap) + status
+		}
+	}
+
 	// Spinner
 	if m.showSpinner && !spinnerOnLeft {
 		// Place spinner on the right
@@ -1060,6 +2035,11 @@ func (m Model) titleView() string {
 }
 
 func (m Model) statusView() string {
+	return m.Styles.StatusBar.Render(m.statusText())
+}
+
+// statusText returns the contents of the status bar.
+func (m Model) statusText() string {
 	var status string
 
 	totalItems := len(m.items)
@@ -1103,7 +2083,7 @@ func (m Model) statusView() string {
 		status += m.Styles.StatusBarFilterCount.Render(fmt.Sprintf("%d filtered", numFiltered))
 	}
 
-	return m.Styles.StatusBar.Render(status)
+	return status
 }
 
 func (m Model) paginationView() string {
@@ -1111,13 +2091,20 @@ func (m Model) paginationView() string {
 		return ""
 	}
 
-	s := m.Paginator.View()
+	var s string
+	if m.moreIndicator != nil {
+		n := len(m.VisibleItems())
+		start, end := m.Paginator.GetSliceBounds(n)
+		s = m.moreIndicator(start, n-end)
+	} else {
+		s = m.Paginator.View()
 
-	// If the dot pagination is wider than the width of the window
-	// use the arabic paginator.
-	if ansi.PrintableRuneWidth(s) > m.width {
-		m.Paginator.Type = paginator.Arabic
-		s = m.Styles.ArabicPagination.Render(m.Paginator.View())
+		// If the dot pagination is wider than the width of the window
+		// use the arabic paginator.
+		if ansi.PrintableRuneWidth(s) > m.width {
+			m.Paginator.Type = paginator.Arabic
+			s = m.Styles.ArabicPagination.Render(m.Paginator.View())
+		}
 	}
 
 	style := m.Styles.PaginationStyle
@@ -1146,7 +2133,10 @@ func (m Model) populatedView() string {
 		docs := items[start:end]
 
 		for i, item := range docs {
-			m.delegate.Render(&b, m, i+start, item)
+			fmt.Fprint(&b, m.renderItem(i+start, item))
+			if m.peekOpen() && i+start == m.Index() {
+				fmt.Fprint(&b, "\n"+m.peekView(item))
+			}
 			if i != len(docs)-1 {
 				fmt.Fprint(&b, strings.Repeat("\n", m.delegate.Spacing()+1))
 			}
@@ -1168,7 +2158,49 @@ func (m Model) populatedView() string {
 	return b.String()
 }
 
+// RenderedItems returns the items on the current page as they're rendered by
+// the delegate, in order, reflecting the selection and filter. It's useful
+// for testing delegates without picking apart the output of View.
+func (m Model) RenderedItems() []string {
+	items := m.VisibleItems()
+	start, end := m.Paginator.GetSliceBounds(len(items))
+
+	rendered := make([]string, 0, end-start)
+	for i, item := range items[start:end] {
+		rendered = append(rendered, m.renderItem(i+start, item))
+	}
+	return rendered
+}
+
+// renderItem renders the item at the given visible index with the delegate.
+func (m Model) renderItem(index int, item Item) string {
+	var b strings.Builder
+	m.delegate.Render(&b, m, index, item)
+	return b.String()
+}
+
+// peekView renders the details of an item, truncated or padded to the
+// reserved height.
+func (m Model) peekView(item Item) string {
+	lines := strings.Split(m.peekFunc(item), "\n")
+	if len(lines) > m.peekHeight {
+		lines = lines[:m.peekHeight]
+	}
+	// Fill the reserved height so the items below stay where they're expected
+	// to be, such as when mapping mouse events to items.
+	for len(lines) < m.peekHeight {
+		lines = append(lines, "")
+	}
+	for i, line := range lines {
+		lines[i] = m.Styles.PeekDetail.Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) helpView() string {
+	if m.helpFunc != nil {
+		return m.helpFunc(m)
+	}
 	return m.Styles.HelpStyle.Render(m.Help.View(m))
 }
 
@@ -1182,25 +2214,52 @@ func filterItems(m Model) tea.Cmd {
 			return FilterMatchesMsg(m.itemsAsFilterItems()) // return nothing
 		}
 
-		targets := []string{}
-		items := m.items
-
-		for _, t := range items {
-			targets = append(targets, t.FilterValue())
-		}
-
+		// Pinned items are filtered separately so that they stay above the
+		// rest of the items.
 		filterMatches := []filteredItem{}
-		for _, r := range m.Filter(m.FilterInput.Value(), targets) {
-			filterMatches = append(filterMatches, filteredItem{
-				item:    items[r.Index],
-				matches: r.MatchedIndexes,
-			})
-		}
+		filterMatches = append(filterMatches, m.filterMatches(m.pinned, true)...)
+		filterMatches = append(filterMatches, m.filterMatches(m.items, false)...)
 
 		return FilterMatchesMsg(filterMatches)
 	}
 }
 
+// filterMatches filters and ranks the given items against the current filter.
+func (m Model) filterMatches(items []Item, pinned bool) []filteredItem {
+	if len(items) == 0 {
+		return nil
+	}
+
+	targets := []string{}
+	for _, t := range items {
+		targets = append(targets, t.FilterValue())
+	}
+
+	ranks := m.Filter(m.FilterInput.Value(), targets)
+	if m.filterTiebreak != nil {
+		sort.SliceStable(ranks, func(i, j int) bool {
+			if ranks[i].Score != ranks[j].Score {
+				return ranks[i].Score > ranks[j].Score
+			}
+			return m.filterTiebreak(items[ranks[i].Index], items[ranks[j].Index])
+		})
+	}
+	if m.RankFunc != nil {
+		ranks = m.RankFunc(ranks)
+	}
+
+	filterMatches := []filteredItem{}
+	for _, r := range ranks {
+		filterMatches = append(filterMatches, filteredItem{
+			index:   r.Index,
+			item:    items[r.Index],
+			matches: r.MatchedIndexes,
+			pinned:  pinned,
+		})
+	}
+	return filterMatches
+}
+
 func insertItemIntoSlice(items []Item, item Item, index int) []Item {
 	if items == nil {
 		return []Item{item}
@@ -1227,13 +2286,26 @@ func removeItemFromSlice(i []Item, index int) []Item {
 	return i[:len(i)-1]
 }
 
+// Remove the match for the item at the given index in the list's items from a
+// slice of matches, updating the indices of the matches for the items after
+// it. This runs in O(n).
 func removeFilterMatchFromSlice(i []filteredItem, index int) []filteredItem {
-	if index >= len(i) {
-		return i // noop
+	matches := i[:0]
+	for _, match := range i {
+		if !match.pinned {
+			if match.index == index {
+				continue
+			}
+			if match.index > index {
+				match.index--
+			}
+		}
+		matches = append(matches, match)
 	}
-	copy(i[index:], i[index+1:])
-	i[len(i)-1] = filteredItem{}
-	return i[:len(i)-1]
+	for j := len(matches); j < len(i); j++ {
+		i[j] = filteredItem{}
+	}
+	return matches
 }
 
 func countEnabledBindings(groups [][]key.Binding) (agg int) {
@@ -1253,3 +2325,10 @@ func max(a, b int) int {
 	}
 	return b
 }
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -3,10 +3,13 @@ package list
 import (
 	"fmt"
 	"io"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type item string
@@ -72,3 +75,836 @@ func TestCustomStatusBarItemName(t *testing.T) {
 		t.Fatalf("Error: expected view to contain %s", expected)
 	}
 }
+
+func TestGoToStartAndEnd(t *testing.T) {
+	items := make([]Item, 25)
+	for i := range items {
+		items[i] = item(fmt.Sprintf("item %d", i))
+	}
+	list := New(items, itemDelegate{}, 10, 10)
+
+	list.GoToEnd()
+	if list.Index() != len(items)-1 {
+		t.Fatalf("Error: expected index %d, got %d", len(items)-1, list.Index())
+	}
+	if !list.Paginator.OnLastPage() {
+		t.Fatal("Error: expected to be on the last page")
+	}
+
+	list.GoToStart()
+	if list.Index() != 0 || list.Paginator.Page != 0 {
+		t.Fatalf("Error: expected index 0 on page 0, got %d on page %d", list.Index(), list.Paginator.Page)
+	}
+}
+
+func TestClearFilterOnActivateKeepsSelection(t *testing.T) {
+	items := []Item{item("foo"), item("bar"), item("baz")}
+	list := New(items, itemDelegate{}, 10, 10)
+	list.SetClearFilterOnActivate(true)
+
+	list.FilterInput.SetValue("ba")
+	list.filterState = FilterApplied
+	list.filteredItems = filteredItems{
+		{index: 1, item: items[1]},
+		{index: 2, item: items[2]},
+	}
+	list.updatePagination()
+	list.updateKeybindings()
+	list.Select(1)
+
+	list, _ = list.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if list.FilterState() != Unfiltered {
+		t.Fatalf("Error: expected filter to be cleared, got %s", list.FilterState())
+	}
+	if list.Index() != 2 {
+		t.Fatalf("Error: expected index 2 to remain selected, got %d", list.Index())
+	}
+}
+
+type groupItem string
+
+func (g groupItem) FilterValue() string { return string(g) }
+func (g groupItem) GroupID() string     { return string(g) }
+
+func TestCollapsedGroupsHideChildren(t *testing.T) {
+	items := []Item{
+		groupItem("fruit"), item("apple"), item("pear"),
+		groupItem("veg"), item("leek"),
+	}
+	list := New(items, itemDelegate{}, 10, 10)
+
+	list, _ = list.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !list.GroupCollapsed("fruit") {
+		t.Fatal("Error: expected activating a group to collapse it")
+	}
+	if n := len(list.VisibleItems()); n != 3 {
+		t.Fatalf("Error: expected 3 visible items, got %d", n)
+	}
+
+	list.CursorDown()
+	if list.SelectedItem() != groupItem("veg") {
+		t.Fatalf("Error: expected navigation to skip collapsed children, got %v", list.SelectedItem())
+	}
+}
+
+func TestCollapsingGroupSelectsHeader(t *testing.T) {
+	items := []Item{groupItem("fruit")}
+	for i := 0; i < 9; i++ {
+		items = append(items, item(fmt.Sprint(i)))
+	}
+	list := New(items, itemDelegate{}, 10, 20)
+	list.Select(7)
+
+	list.SetGroupCollapsed("fruit", true)
+	if list.Index() != 0 || list.SelectedItem() != groupItem("fruit") {
+		t.Fatalf("Error: expected the group header to be selected, got %v at index %d", list.SelectedItem(), list.Index())
+	}
+
+	list.Select(0)
+	list.SetGroupCollapsed("fruit", false)
+	if list.SelectedItem() != groupItem("fruit") {
+		t.Fatalf("Error: expected the group header to stay selected, got %v", list.SelectedItem())
+	}
+}
+
+func TestCollapsedGroupsAreNotSharedBetweenCopies(t *testing.T) {
+	list := New([]Item{groupItem("fruit"), item("apple"), item("pear")}, itemDelegate{}, 10, 10)
+	snapshot := list
+
+	list.SetGroupCollapsed("fruit", true)
+	if snapshot.GroupCollapsed("fruit") || len(snapshot.VisibleItems()) != 3 {
+		t.Fatalf("Error: expected the copy's group to stay expanded, got %v", snapshot.VisibleItems())
+	}
+	if len(list.VisibleItems()) != 1 {
+		t.Fatalf("Error: expected only the group header to be visible, got %v", list.VisibleItems())
+	}
+}
+
+func TestSelectionChanged(t *testing.T) {
+	list := New([]Item{item("foo"), item("bar")}, itemDelegate{}, 10, 10)
+	if cmd := list.selectionChanged(); cmd != nil {
+		t.Fatalf("expected no selection change, got %v", cmd())
+	}
+
+	list.Select(1)
+	cmd := list.selectionChanged()
+	if cmd == nil {
+		t.Fatal("expected a selection change")
+	}
+	msg, ok := cmd().(SelectionChangedMsg)
+	if !ok || msg.Index != 1 || msg.Item != item("bar") {
+		t.Fatalf("unexpected message: %#v", msg)
+	}
+
+	if cmd := list.selectionChanged(); cmd != nil {
+		t.Fatalf("expected selection change to be sent once, got %v", cmd())
+	}
+}
+
+func TestSpacingAffectsItemsPerPage(t *testing.T) {
+	d := NewDefaultDelegate()
+	d.ShowDescription = false
+	list := New([]Item{item("foo"), item("bar")}, d, 10, 20)
+	list.SetShowTitle(false)
+	list.SetShowStatusBar(false)
+	list.SetShowHelp(false)
+	list.SetShowPagination(false)
+	list.SetFilteringEnabled(false)
+
+	for _, tc := range []struct {
+		spacing, perPage int
+	}{
+		{-1, 20},
+		{0, 20},
+		{1, 10},
+		{3, 5},
+	} {
+		d.SetSpacing(tc.spacing)
+		list.SetDelegate(d)
+		if list.Paginator.PerPage != tc.perPage {
+			t.Errorf("spacing %d: expected %d items per page, got %d", tc.spacing, tc.perPage, list.Paginator.PerPage)
+		}
+	}
+}
+
+func TestPinnedItemsStayOnTop(t *testing.T) {
+	list := New([]Item{item("foo"), item("bar")}, itemDelegate{}, 10, 10)
+	list.SetPinnedItems([]Item{item("baz")})
+
+	visible := list.VisibleItems()
+	if len(visible) != 3 || visible[0] != item("baz") {
+		t.Fatalf("Error: expected pinned item first, got %v", visible)
+	}
+	if !list.IsPinned(0) || list.IsPinned(1) {
+		t.Fatal("Error: expected only the first visible item to be pinned")
+	}
+	if i := list.itemIndex(1); i != 0 {
+		t.Fatalf("Error: expected visible item 1 to be item 0, got %d", i)
+	}
+
+	// Match the first target of each section.
+	list.Filter = func(term string, targets []string) []Rank {
+		return []Rank{{Index: 0}}
+	}
+	list.FilterInput.SetValue("x")
+	list.filterState = FilterApplied
+	msg := filterItems(list)().(FilterMatchesMsg)
+	list, _ = list.Update(msg)
+
+	visible = list.VisibleItems()
+	if len(visible) != 2 || visible[0] != item("baz") || visible[1] != item("foo") {
+		t.Fatalf("Error: expected filtered pinned item first, got %v", visible)
+	}
+}
+
+type structItem struct {
+	name string
+	tags []string
+}
+
+func (i structItem) FilterValue() string { return i.name }
+
+func TestSetItemsKeepsSelectionWithEqual(t *testing.T) {
+	list := New([]Item{
+		structItem{name: "foo"},
+		structItem{name: "bar"},
+		structItem{name: "baz"},
+	}, itemDelegate{}, 10, 10)
+	list.Equal = func(a, b Item) bool {
+		return a.(structItem).name == b.(structItem).name
+	}
+	list.Select(1)
+
+	list.SetItems([]Item{
+		structItem{name: "baz"},
+		structItem{name: "qux"},
+		structItem{name: "bar", tags: []string{"new"}},
+	})
+
+	if list.Index() != 2 {
+		t.Fatalf("Error: expected bar to remain selected at index 2, got %d", list.Index())
+	}
+}
+
+func TestSetItemsWhileFiltered(t *testing.T) {
+	list := New([]Item{item("foo"), item("bar")}, itemDelegate{}, 10, 10)
+	list.Filter = func(term string, targets []string) []Rank {
+		ranks := make([]Rank, len(targets))
+		for i := range targets {
+			ranks[i] = Rank{Index: i}
+		}
+		return ranks
+	}
+	list.FilterInput.SetValue("ba")
+	list.filterState = FilterApplied
+	list.filteredItems = filteredItems{{index: 1, item: item("bar")}}
+	list.updatePagination()
+
+	items := make([]Item, 50)
+	for i := range items {
+		items[i] = item(fmt.Sprint(i))
+	}
+	cmd := list.SetItems(items)
+	if cmd == nil {
+		t.Fatal("Error: expected a command to re-apply the filter")
+	}
+	list, _ = list.Update(cmd())
+
+	if list.FilterState() != FilterApplied {
+		t.Fatalf("Error: expected filter to remain applied, got %s", list.FilterState())
+	}
+	if list.FilterValue() != "ba" {
+		t.Fatalf("Error: expected filter term to be preserved, got %q", list.FilterValue())
+	}
+	if n := len(list.VisibleItems()); n != 50 {
+		t.Fatalf("Error: expected filter to be applied to the new items, got %d items", n)
+	}
+	if list.Paginator.TotalPages < 2 {
+		t.Fatalf("Error: expected pagination to reflect the new items, got %d pages", list.Paginator.TotalPages)
+	}
+}
+
+func TestActiveIndex(t *testing.T) {
+	list := New([]Item{item("foo"), item("bar"), item("baz")}, itemDelegate{}, 10, 10)
+	list.SetActiveIndex(1)
+	list.CursorDown()
+	list.CursorDown()
+
+	if list.ActiveIndex() != 1 || !list.IsActive(1) || list.IsActive(2) {
+		t.Fatalf("Error: expected item 1 to remain active, got %d", list.ActiveIndex())
+	}
+
+	list.InsertItem(0, item("qux"))
+	if list.ActiveIndex() != 2 {
+		t.Fatalf("Error: expected active index to follow inserted items, got %d", list.ActiveIndex())
+	}
+
+	list.RemoveItem(2)
+	if list.ActiveIndex() != -1 {
+		t.Fatalf("Error: expected removing the active item to clear it, got %d", list.ActiveIndex())
+	}
+}
+
+func TestNextPageKeepsCursorOnItem(t *testing.T) {
+	items := make([]Item, 5)
+	for i := range items {
+		items[i] = item(fmt.Sprint(i))
+	}
+	list := New(items, itemDelegate{}, 10, 10)
+	list.SetShowTitle(false)
+	list.SetShowStatusBar(false)
+	list.SetShowHelp(false)
+	list.SetShowFilter(false)
+	list.Paginator.PerPage = 3
+	list.Paginator.SetTotalPages(len(items))
+	list.Select(2)
+
+	list, _ = list.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	if list.Paginator.Page != 1 {
+		t.Fatalf("Error: expected page 1, got %d", list.Paginator.Page)
+	}
+	if list.Index() != 4 {
+		t.Fatalf("Error: expected cursor on the last item, got index %d", list.Index())
+	}
+}
+
+func TestRemappedFilterKey(t *testing.T) {
+	list := New([]Item{item("foo"), item("bar")}, itemDelegate{}, 10, 10)
+	list.KeyMap.Filter.SetKeys("s")
+	list.KeyMap.Filter.SetHelp("s", "filter")
+
+	list, _ = list.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if list.FilterState() != Filtering {
+		t.Fatalf("Error: expected the remapped key to start filtering, got %s", list.FilterState())
+	}
+}
+
+func TestSetPage(t *testing.T) {
+	items := make([]Item, 5)
+	for i := range items {
+		items[i] = item(fmt.Sprint(i))
+	}
+	list := New(items, itemDelegate{}, 10, 10)
+	list.Paginator.PerPage = 3
+	list.Paginator.SetTotalPages(len(items))
+	list.Select(2)
+
+	list.SetPage(5)
+	if list.Page() != 1 || list.TotalPages() != 2 {
+		t.Fatalf("Error: expected page 1 of 2, got page %d of %d", list.Page(), list.TotalPages())
+	}
+	if list.Index() != 4 {
+		t.Fatalf("Error: expected cursor on the last item, got index %d", list.Index())
+	}
+}
+
+func TestMouseDragReordersItems(t *testing.T) {
+	list := New([]Item{item("a"), item("b"), item("c")}, itemDelegate{}, 10, 10)
+	list.SetShowTitle(false)
+	list.SetShowFilter(false)
+	list.SetShowStatusBar(false)
+	list.MouseDragEnabled = true
+	list.YPosition = 2
+
+	for _, msg := range []tea.MouseMsg{
+		{Type: tea.MouseLeft, Y: 2},
+		{Type: tea.MouseLeft, Y: 3},
+		{Type: tea.MouseLeft, Y: 4},
+		{Type: tea.MouseRelease, Y: 4},
+	} {
+		list, _ = list.Update(msg)
+	}
+
+	expected := []Item{item("b"), item("c"), item("a")}
+	for i, it := range list.Items() {
+		if it != expected[i] {
+			t.Fatalf("Error: expected %v, got %v", expected, list.Items())
+		}
+	}
+	if list.Index() != 2 {
+		t.Fatalf("Error: expected the dragged item to be selected, got index %d", list.Index())
+	}
+}
+
+func TestMouseDragOntoCollapsedGroup(t *testing.T) {
+	list := New([]Item{item("a"), item("b"), groupItem("g"), item("c")}, itemDelegate{}, 10, 10)
+	list.SetShowTitle(false)
+	list.SetShowFilter(false)
+	list.SetShowStatusBar(false)
+	list.MouseDragEnabled = true
+	list.SetGroupCollapsed("g", true)
+
+	for _, msg := range []tea.MouseMsg{
+		{Type: tea.MouseLeft, Y: 0},
+		{Type: tea.MouseLeft, Y: 1},
+		{Type: tea.MouseLeft, Y: 2},
+		{Type: tea.MouseRelease, Y: 2},
+	} {
+		list, _ = list.Update(msg)
+	}
+
+	expected := []Item{item("b"), item("a"), groupItem("g"), item("c")}
+	for i, it := range list.Items() {
+		if it != expected[i] {
+			t.Fatalf("Error: expected %v, got %v", expected, list.Items())
+		}
+	}
+	if list.SelectedItem() != item("a") {
+		t.Fatalf("Error: expected the dragged item to stay selected, got %v", list.SelectedItem())
+	}
+}
+
+func TestDefaultFilterKeepsOrderOfEqualScores(t *testing.T) {
+	ranks := DefaultFilter("a", []string{"ab", "ac", "ad", "ae"})
+	for i, r := range ranks {
+		if r.Index != i {
+			t.Fatalf("Error: expected matches in their original order, got %v", ranks)
+		}
+	}
+}
+
+func TestSetFilterTiebreak(t *testing.T) {
+	list := New([]Item{item("zeta"), item("beta"), item("gamma"), item("alpha")}, itemDelegate{}, 10, 10)
+	list.SetFilterTiebreak(func(a, b Item) bool {
+		return a.(item) < b.(item)
+	})
+
+	// Score gamma highest and tie the rest.
+	list.Filter = func(term string, targets []string) []Rank {
+		return []Rank{{Index: 0}, {Index: 1}, {Index: 2, Score: 1}, {Index: 3}}
+	}
+	list.FilterInput.SetValue("a")
+	list.filterState = FilterApplied
+	msg := filterItems(list)().(FilterMatchesMsg)
+	list, _ = list.Update(msg)
+
+	visible := list.VisibleItems()
+	want := []Item{item("gamma"), item("alpha"), item("beta"), item("zeta")}
+	if len(visible) != len(want) {
+		t.Fatalf("Error: expected %v, got %v", want, visible)
+	}
+	for i := range want {
+		if visible[i] != want[i] {
+			t.Fatalf("Error: expected equal scores broken by the tiebreak %v, got %v", want, visible)
+		}
+	}
+}
+
+func TestSetFilterPrompt(t *testing.T) {
+	list := New([]Item{item("foo")}, itemDelegate{}, 20, 10)
+	list.SetFilterPrompt("Search: ")
+	list, _ = list.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+
+	if !strings.Contains(list.View(), "Search: ") {
+		t.Fatal("Error: expected the custom filter prompt to be shown")
+	}
+}
+
+func TestMultiSelection(t *testing.T) {
+	list := New([]Item{item("a"), item("b"), item("c")}, itemDelegate{}, 10, 10)
+	list.SetSelected(0, true)
+	list.ToggleSelected(2)
+	list.ToggleSelected(0)
+	list.SetSelected(1, true)
+
+	list.RemoveItem(0)
+	selected := list.SelectedItems()
+	if len(selected) != 2 || selected[0] != item("b") || selected[1] != item("c") {
+		t.Fatalf("Error: expected b and c to be selected, got %v", selected)
+	}
+	if !list.IsSelected(0) || !list.IsSelected(1) {
+		t.Fatal("Error: expected the selection to follow the items")
+	}
+
+	list.ClearSelection()
+	if len(list.SelectedItems()) != 0 {
+		t.Fatal("Error: expected the selection to be empty")
+	}
+}
+
+func TestRemoveItemWhileFiltered(t *testing.T) {
+	items := []Item{item("foo"), item("bar"), item("baz")}
+	list := New(items, itemDelegate{}, 10, 10)
+	list.KeyMap.RemoveItem.SetKeys("x")
+
+	list.FilterInput.SetValue("ba")
+	list.filterState = FilterApplied
+	list.filteredItems = filteredItems{
+		{index: 1, item: items[1]},
+		{index: 2, item: items[2]},
+	}
+	list.updatePagination()
+	list.updateKeybindings()
+	list.Select(1)
+
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}, list.KeyMap.RemoveItem) {
+		t.Fatal("Error: expected the remove binding to be enabled")
+	}
+
+	msg := list.removeSelected()()
+	if removed, ok := msg.(ItemRemovedMsg); !ok || removed.Index != 2 || removed.Item != item("baz") {
+		t.Fatalf("Error: expected baz at index 2 to be removed, got %+v", msg)
+	}
+	if len(list.filteredItems) != 1 || list.filteredItems[0].item != item("bar") {
+		t.Fatalf("Error: expected only bar to remain filtered, got %v", list.filteredItems)
+	}
+	if list.Index() != 0 {
+		t.Fatalf("Error: expected the cursor to move to the remaining item, got %d", list.Index())
+	}
+}
+
+func TestSelectedItemMatches(t *testing.T) {
+	items := []Item{item("foo"), item("bar"), item("baz")}
+	list := New(items, itemDelegate{}, 10, 10)
+	if list.SelectedItemMatches() != nil {
+		t.Fatal("Error: expected no matches without a filter")
+	}
+
+	list.FilterInput.SetValue("ba")
+	list.filterState = FilterApplied
+	list.filteredItems = filteredItems{
+		{index: 1, item: items[1], matches: []int{0, 1}},
+		{index: 2, item: items[2], matches: []int{0, 1}},
+	}
+	list.updatePagination()
+	list.Select(1)
+
+	if m := list.SelectedItemMatches(); len(m) != 2 || m[0] != 0 || m[1] != 1 {
+		t.Fatalf("Error: expected matches [0 1], got %v", m)
+	}
+}
+
+func TestStatusBarInTitle(t *testing.T) {
+	list := New([]Item{item("foo"), item("bar")}, itemDelegate{}, 30, 10)
+	perPage := list.Paginator.PerPage
+	list.SetStatusBarInTitle(true)
+
+	if list.Paginator.PerPage <= perPage {
+		t.Fatalf("Error: expected more items per page, got %d", list.Paginator.PerPage)
+	}
+	firstLine := strings.Split(list.View(), "\n")[0]
+	if !strings.Contains(firstLine, "List") || !strings.Contains(firstLine, "2 items") {
+		t.Fatalf("Error: expected the title and status on one line, got %q", firstLine)
+	}
+}
+
+func TestItemIndex(t *testing.T) {
+	items := []Item{item("foo"), item("bar"), item("baz")}
+	list := New(items, itemDelegate{}, 10, 10)
+	list.filterState = FilterApplied
+	list.filteredItems = filteredItems{
+		{index: 1, item: items[1]},
+		{index: 2, item: items[2]},
+	}
+
+	for visible, expected := range []int{1, 2, -1} {
+		if i := list.ItemIndex(visible); i != expected {
+			t.Fatalf("Error: expected visible index %d to map to %d, got %d", visible, expected, i)
+		}
+	}
+}
+
+func TestPeekFollowsSelection(t *testing.T) {
+	list := New([]Item{item("foo"), item("bar"), item("baz")}, itemDelegate{}, 30, 20)
+	perPage := list.Paginator.PerPage
+	list.SetPeekFunc(func(i Item) string { return "about " + string(i.(item)) }, 2)
+
+	list, _ = list.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	if !list.Peeking() {
+		t.Fatal("Error: expected peek to be open")
+	}
+	if list.Paginator.PerPage != perPage-2 {
+		t.Fatalf("Error: expected %d items per page, got %d", perPage-2, list.Paginator.PerPage)
+	}
+	if v := list.View(); !strings.Contains(v, "about foo") {
+		t.Fatalf("Error: expected details of the selected item, got %q", v)
+	}
+
+	list.CursorDown()
+	v := list.View()
+	if strings.Contains(v, "about foo") || !strings.Contains(v, "about bar") {
+		t.Fatalf("Error: expected details to follow the selection, got %q", v)
+	}
+}
+
+func TestPeekFillsReservedHeight(t *testing.T) {
+	d := NewDefaultDelegate()
+	d.SetSpacing(0)
+	d.ShowDescription = false
+	items := []Item{accessoryItem{title: "foo"}, accessoryItem{title: "bar"}, accessoryItem{title: "baz"}}
+	list := New(items, d, 30, 20)
+	list.SetPeekFunc(func(i Item) string { return "about " + i.(accessoryItem).title }, 3)
+	list, _ = list.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+
+	row := -1
+	for i, line := range strings.Split(list.View(), "\n") {
+		if strings.Contains(line, "bar") {
+			row = i
+			break
+		}
+	}
+	if row < 0 {
+		t.Fatal("Error: expected bar to be rendered")
+	}
+	if i := list.itemAt(row); i != 1 {
+		t.Fatalf("Error: expected row %d to map to item 1, got %d", row, i)
+	}
+}
+
+func TestRenderedItems(t *testing.T) {
+	list := New([]Item{item("foo"), item("bar"), item("baz")}, itemDelegate{}, 10, 8)
+	list.SetShowHelp(false)
+	list.SetShowStatusBar(false)
+	list.SetShowTitle(false)
+	list.SetShowFilter(false)
+	list.SetShowPagination(false)
+	list.SetHeight(2)
+
+	rendered := list.RenderedItems()
+	if len(rendered) != 2 || !strings.Contains(rendered[0], "1. foo") || !strings.Contains(rendered[1], "2. bar") {
+		t.Fatalf("Error: expected the first page of items, got %q", rendered)
+	}
+
+	list.NextPage()
+	rendered = list.RenderedItems()
+	if len(rendered) != 1 || !strings.Contains(rendered[0], "3. baz") {
+		t.Fatalf("Error: expected the second page of items, got %q", rendered)
+	}
+}
+
+func TestRemoveItemHelpOnlyWhenBound(t *testing.T) {
+	list := New([]Item{item("foo")}, itemDelegate{}, 10, 10)
+
+	hasRemove := func() bool {
+		for _, group := range list.FullHelp() {
+			for _, b := range group {
+				if b.Help().Desc == "remove" {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	if hasRemove() {
+		t.Fatal("Error: expected the unbound remove binding to be left out of the help")
+	}
+	list.KeyMap.RemoveItem.SetKeys("x")
+	if !hasRemove() {
+		t.Fatal("Error: expected the remove binding in the help once it's bound")
+	}
+}
+
+type titleItem string
+
+func (i titleItem) FilterValue() string { return string(i) }
+func (i titleItem) Title() string       { return string(i) }
+func (i titleItem) Description() string { return "" }
+
+func TestDraggedItemIsMarked(t *testing.T) {
+	d := NewDefaultDelegate()
+	d.ShowDescription = false
+	list := New([]Item{titleItem("a"), titleItem("b")}, d, 20, 10)
+	list.SetShowTitle(false)
+	list.SetShowFilter(false)
+	list.SetShowStatusBar(false)
+	list.MouseDragEnabled = true
+
+	marker := lipgloss.ThickBorder().Left
+	if strings.Contains(list.View(), marker) {
+		t.Fatal("Error: expected no drag marker before dragging")
+	}
+
+	list, _ = list.Update(tea.MouseMsg{Type: tea.MouseLeft, Y: 2})
+	if !list.Dragging() || !strings.Contains(list.View(), marker+" b") {
+		t.Fatalf("Error: expected the dragged item to be marked, got %q", list.View())
+	}
+
+	list, _ = list.Update(tea.MouseMsg{Type: tea.MouseRelease, Y: 2})
+	if list.Dragging() || strings.Contains(list.View(), marker) {
+		t.Fatal("Error: expected the drag marker to be removed on release")
+	}
+}
+
+func TestSetItemsKeepsSelectionOfNonComparableItems(t *testing.T) {
+	list := New([]Item{
+		structItem{name: "foo", tags: []string{"a"}},
+		structItem{name: "bar", tags: []string{"b"}},
+	}, itemDelegate{}, 10, 10)
+	list.Select(1)
+
+	list.SetItems([]Item{
+		structItem{name: "baz"},
+		structItem{name: "foo", tags: []string{"a"}},
+		structItem{name: "bar", tags: []string{"b"}},
+	})
+
+	if list.Index() != 2 {
+		t.Fatalf("Error: expected bar to remain selected at index 2, got %d", list.Index())
+	}
+}
+
+func TestSelectionChangedWithNonComparableItems(t *testing.T) {
+	list := New([]Item{
+		structItem{name: "foo", tags: []string{"a"}},
+		structItem{name: "bar"},
+	}, itemDelegate{}, 10, 10)
+	if cmd := list.selectionChanged(); cmd != nil {
+		t.Fatalf("Error: expected no selection change, got %v", cmd())
+	}
+
+	// Replace the selected item with a different one at the same index.
+	list.SetItems([]Item{
+		structItem{name: "baz", tags: []string{"b"}},
+		structItem{name: "bar"},
+	})
+	cmd := list.selectionChanged()
+	if cmd == nil {
+		t.Fatal("Error: expected a selection change")
+	}
+	if msg, ok := cmd().(SelectionChangedMsg); !ok || msg.Index != 0 || msg.Item.(structItem).name != "baz" {
+		t.Fatalf("Error: unexpected message: %#v", msg)
+	}
+	if cmd := list.selectionChanged(); cmd != nil {
+		t.Fatalf("Error: expected selection change to be sent once, got %v", cmd())
+	}
+}
+
+func TestRemoveSelectedItemWithCollapsedGroup(t *testing.T) {
+	items := []Item{
+		groupItem("fruit"), item("apple"), item("pear"),
+		groupItem("veg"), item("leek"),
+	}
+	list := New(items, itemDelegate{}, 10, 10)
+	list.SetGroupCollapsed("fruit", true)
+	list.Select(2)
+
+	list.RemoveItem(list.ItemIndex(list.Index()))
+
+	expected := []Item{groupItem("fruit"), item("apple"), item("pear"), groupItem("veg")}
+	if fmt.Sprint(list.Items()) != fmt.Sprint(expected) {
+		t.Fatalf("Error: expected leek to be removed, got %v", list.Items())
+	}
+}
+
+func TestRemoveSelectedItemWithPinnedItem(t *testing.T) {
+	list := New([]Item{item("foo"), item("bar")}, itemDelegate{}, 10, 10)
+	list.SetPinnedItems([]Item{item("baz")})
+	list.Select(2)
+
+	list.RemoveItem(list.ItemIndex(list.Index()))
+	if fmt.Sprint(list.Items()) != fmt.Sprint([]Item{item("foo")}) {
+		t.Fatalf("Error: expected bar to be removed, got %v", list.Items())
+	}
+
+	// Pinned items aren't in Items, so selecting one removes nothing.
+	list.Select(0)
+	list.RemoveItem(list.ItemIndex(list.Index()))
+	if len(list.Items()) != 1 || len(list.PinnedItems()) != 1 {
+		t.Fatalf("Error: expected no item to be removed, got %v", list.Items())
+	}
+}
+
+func TestMultiSelectionIsNotSharedBetweenCopies(t *testing.T) {
+	list := New([]Item{item("a"), item("b")}, itemDelegate{}, 10, 10)
+	list.SetSelected(0, true)
+
+	other := list
+	other.SetSelected(1, true)
+	other.SetSelected(0, false)
+
+	if !list.IsSelected(0) || list.IsSelected(1) {
+		t.Fatal("Error: expected changes to a copy to leave the original's selection alone")
+	}
+}
+
+func TestMultiSelectReservesCheckmarkColumn(t *testing.T) {
+	d := NewDefaultDelegate()
+	d.ShowDescription = false
+	list := New([]Item{titleItem("a"), titleItem("b")}, d, 20, 10)
+	list.SetShowTitle(false)
+	list.SetShowFilter(false)
+	list.SetShowStatusBar(false)
+	list.SetMultiSelectEnabled(true)
+
+	before := list.RenderedItems()
+	list.SetSelected(0, true)
+	after := list.RenderedItems()
+
+	if strings.Index(before[1], "b") != strings.Index(after[1], "b") {
+		t.Fatalf("Error: expected items not to shift on the first selection, got %q and %q", before[1], after[1])
+	}
+	if !strings.Contains(after[0], checkmark) {
+		t.Fatalf("Error: expected a checkmark on the selected item, got %q", after[0])
+	}
+}
+
+type accessoryItem struct {
+	title, accessory string
+}
+
+func (i accessoryItem) FilterValue() string { return i.title }
+func (i accessoryItem) Title() string       { return i.title }
+func (i accessoryItem) Description() string { return "" }
+func (i accessoryItem) Accessory() string   { return i.accessory }
+
+func TestAccessoryItem(t *testing.T) {
+	d := NewDefaultDelegate()
+	d.ShowDescription = false
+	list := New([]Item{
+		accessoryItem{title: "short", accessory: "3"},
+		accessoryItem{title: "a title that is far too long", accessory: "12:00"},
+	}, d, 20, 20)
+
+	rendered := list.RenderedItems()
+	if len(rendered) != 2 {
+		t.Fatalf("Error: expected both items on the page, got %q", rendered)
+	}
+	for _, r := range rendered {
+		if w := lipgloss.Width(r); w != 20 {
+			t.Fatalf("Error: expected the accessory flush right at 20 columns, got %d in %q", w, r)
+		}
+	}
+	if !strings.HasSuffix(rendered[0], "3") || !strings.Contains(rendered[0], "short") {
+		t.Fatalf("Error: expected the title and accessory, got %q", rendered[0])
+	}
+	if !strings.HasSuffix(rendered[1], "12:00") || !strings.Contains(rendered[1], ellipsis+" 12:00") {
+		t.Fatalf("Error: expected the title truncated to leave room for the accessory, got %q", rendered[1])
+	}
+}
+
+// cmdMsgs runs cmd and returns its messages, unpacking batches.
+func cmdMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Slice {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for i := 0; i < v.Len(); i++ {
+		if c, ok := v.Index(i).Interface().(tea.Cmd); ok {
+			msgs = append(msgs, cmdMsgs(c)...)
+		}
+	}
+	return msgs
+}
+
+func TestActivateSendsItemActivatedMsg(t *testing.T) {
+	list := New([]Item{item("foo"), item("bar"), item("baz")}, itemDelegate{}, 10, 10)
+	list.Select(1)
+
+	_, cmd := list.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	var activated []ItemActivatedMsg
+	for _, msg := range cmdMsgs(cmd) {
+		if msg, ok := msg.(ItemActivatedMsg); ok {
+			activated = append(activated, msg)
+		}
+	}
+	if len(activated) != 1 || activated[0].Index != 1 || activated[0].Item != item("bar") {
+		t.Fatalf("Error: expected bar at index 1 to be activated once, got %+v", activated)
+	}
+}
@@ -0,0 +1,158 @@
+package list
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const defaultMasterDetailSplit = 0.4
+
+// MasterDetailKeyMap defines keybindings for the MasterDetail component.
+type MasterDetailKeyMap struct {
+	SwitchFocus key.Binding
+}
+
+// DefaultMasterDetailKeyMap returns a default set of keybindings for the
+// MasterDetail component.
+func DefaultMasterDetailKeyMap() MasterDetailKeyMap {
+	return MasterDetailKeyMap{
+		SwitchFocus: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "switch pane"),
+		),
+	}
+}
+
+// MasterDetail is a small composition of a list on the left and a viewport on
+// the right. Whenever the list's selection changes the viewport's content is
+// replaced with the result of DetailFunc for the newly selected item. Key
+// events are forwarded to whichever pane is focused, and mouse events to the
+// pane under the mouse.
+type MasterDetail struct {
+	List   Model
+	Detail viewport.Model
+	KeyMap MasterDetailKeyMap
+
+	// DetailFunc renders the detail pane content for the given item. It's
+	// called with nil when there's no selected item.
+	DetailFunc func(Item) string
+
+	// Split is the fraction of the total width given to the list. The
+	// remaining width is given to the detail pane. By default this is 0.4.
+	Split float64
+
+	width         int
+	height        int
+	split         float64 // the split the panes were last sized with
+	detailFocused bool
+
+	// The item whose details are shown, used to determine whether the
+	// selection has changed.
+	lastSelected Item
+}
+
+// NewMasterDetail returns a new MasterDetail component wrapping the given list.
+func NewMasterDetail(l Model, detailFunc func(Item) string) MasterDetail {
+	m := MasterDetail{
+		List:       l,
+		Detail:     viewport.New(0, 0),
+		KeyMap:     DefaultMasterDetailKeyMap(),
+		DetailFunc: detailFunc,
+		Split:      defaultMasterDetailSplit,
+	}
+	m.SetSize(l.Width(), l.Height())
+	m.Refresh()
+	return m
+}
+
+// SetSize sets the total width and height of the component, dividing the width
+// between the list and the detail pane according to Split.
+func (m *MasterDetail) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.split = m.Split
+
+	listWidth := int(float64(width) * m.Split)
+	m.List.SetSize(listWidth, height)
+	m.Detail.Width = max(0, width-listWidth)
+	m.Detail.Height = height
+}
+
+// layout resizes the panes if Split has changed since they were last sized.
+func (m *MasterDetail) layout() {
+	if m.Split != m.split {
+		m.SetSize(m.width, m.height)
+	}
+}
+
+// DetailFocused returns whether or not the detail pane is focused.
+func (m MasterDetail) DetailFocused() bool {
+	return m.detailFocused
+}
+
+// SetDetailFocused focuses the detail pane when true, and the list otherwise.
+func (m *MasterDetail) SetDetailFocused(v bool) {
+	m.detailFocused = v
+}
+
+// Refresh re-renders the detail pane for the currently selected item. Call
+// this if an item's details have changed without the selection changing.
+func (m *MasterDetail) Refresh() {
+	m.lastSelected = m.List.SelectedItem()
+
+	if m.DetailFunc == nil {
+		return
+	}
+	m.Detail.SetContent(m.DetailFunc(m.List.SelectedItem()))
+	m.Detail.GotoTop()
+}
+
+// Init exists to satisfy the tea.Model interface for composability purposes.
+func (m MasterDetail) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m MasterDetail) Update(msg tea.Msg) (MasterDetail, tea.Cmd) {
+	var cmd tea.Cmd
+	m.layout()
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+		return m, nil
+
+	case tea.KeyMsg:
+		// Don't steal keys from the list while a filter is being edited.
+		if !m.List.SettingFilter() && key.Matches(msg, m.KeyMap.SwitchFocus) {
+			m.detailFocused = !m.detailFocused
+			return m, nil
+		}
+		if m.detailFocused {
+			m.Detail, cmd = m.Detail.Update(msg)
+			return m, cmd
+		}
+
+	case tea.MouseMsg:
+		if msg.X >= m.List.Width() {
+			m.Detail, cmd = m.Detail.Update(msg)
+			return m, cmd
+		}
+	}
+
+	m.List, cmd = m.List.Update(msg)
+
+	if !m.List.sameItem(m.List.SelectedItem(), m.lastSelected) {
+		m.Refresh()
+	}
+
+	return m, cmd
+}
+
+// View renders the component.
+func (m MasterDetail) View() string {
+	m.layout()
+	return lipgloss.JoinHorizontal(lipgloss.Top, m.List.View(), m.Detail.View())
+}
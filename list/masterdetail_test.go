@@ -0,0 +1,90 @@
+package list
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newTestMasterDetail() MasterDetail {
+	items := []Item{item("foo"), item("bar"), item("baz")}
+	return NewMasterDetail(New(items, itemDelegate{}, 100, 10), func(i Item) string {
+		if i == nil {
+			return "none"
+		}
+		return strings.Repeat("about "+string(i.(item))+"\n", 20)
+	})
+}
+
+func TestMasterDetailSwitchFocus(t *testing.T) {
+	m := newTestMasterDetail()
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if m.List.Index() != 1 || !strings.Contains(m.Detail.View(), "about bar") {
+		t.Fatalf("Error: expected the list to be focused, got index %d", m.List.Index())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if !m.DetailFocused() {
+		t.Fatal("Error: expected the detail pane to be focused")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if m.List.Index() != 1 || m.Detail.YOffset != 1 {
+		t.Fatalf("Error: expected keys to scroll the detail pane, got index %d and y offset %d", m.List.Index(), m.Detail.YOffset)
+	}
+}
+
+func TestMasterDetailResize(t *testing.T) {
+	m := newTestMasterDetail()
+
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 50, Height: 8})
+	if m.List.Width() != 20 || m.Detail.Width != 30 || m.Detail.Height != 8 {
+		t.Fatalf("Error: expected panes of 20 and 30 columns, got %d and %d", m.List.Width(), m.Detail.Width)
+	}
+
+	m.Split = 0.5
+	m, _ = m.Update(nil)
+	if m.List.Width() != 25 || m.Detail.Width != 25 {
+		t.Fatalf("Error: expected a change in split to be applied, got %d and %d", m.List.Width(), m.Detail.Width)
+	}
+}
+
+func TestMasterDetailRefresh(t *testing.T) {
+	m := newTestMasterDetail()
+	m.Detail.LineDown(2)
+
+	// Replacing the selected item refreshes the details, even though the
+	// index stays the same.
+	m.List.SetItems([]Item{item("qux"), item("bar")})
+	m, _ = m.Update(nil)
+	if !strings.Contains(m.Detail.View(), "about qux") || m.Detail.YOffset != 0 {
+		t.Fatalf("Error: expected the details of the new item, got %q", m.Detail.View())
+	}
+
+	// Updates that don't change the selection leave the detail pane alone.
+	m.Detail.LineDown(2)
+	m, _ = m.Update(nil)
+	if m.Detail.YOffset != 2 {
+		t.Fatalf("Error: expected the detail pane to keep its position, got y offset %d", m.Detail.YOffset)
+	}
+}
+
+func TestMasterDetailRoutesMouseEvents(t *testing.T) {
+	m := newTestMasterDetail()
+	m.List.SetShowTitle(false)
+	m.List.SetShowFilter(false)
+	m.List.SetShowStatusBar(false)
+	m.List.MouseDragEnabled = true
+
+	m, _ = m.Update(tea.MouseMsg{Type: tea.MouseLeft, X: 1, Y: 1})
+	if !m.List.Dragging() || m.List.Index() != 1 {
+		t.Fatalf("Error: expected the list to receive the mouse event, got index %d", m.List.Index())
+	}
+	m, _ = m.Update(tea.MouseMsg{Type: tea.MouseRelease, X: 1, Y: 1})
+
+	m, _ = m.Update(tea.MouseMsg{Type: tea.MouseWheelDown, X: 50, Y: 1})
+	if m.Detail.YOffset == 0 {
+		t.Fatal("Error: expected the detail pane to receive the mouse event")
+	}
+}
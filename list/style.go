@@ -5,8 +5,9 @@ import (
 )
 
 const (
-	bullet   = "•"
-	ellipsis = "…"
+	bullet    = "•"
+	ellipsis  = "…"
+	checkmark = "✓"
 )
 
 // Styles contains style definitions for this list component. By default, these
@@ -29,6 +30,9 @@ type Styles struct {
 
 	NoItems lipgloss.Style
 
+	// Details shown below the selected item. See Model.SetPeekFunc.
+	PeekDetail lipgloss.Style
+
 	PaginationStyle lipgloss.Style
 	HelpStyle       lipgloss.Style
 
@@ -77,6 +81,10 @@ func DefaultStyles() (s Styles) {
 	s.NoItems = lipgloss.NewStyle().
 		Foreground(lipgloss.AdaptiveColor{Light: "#909090", Dark: "#626262"})
 
+	s.PeekDetail = lipgloss.NewStyle().
+		Foreground(subduedColor).
+		PaddingLeft(4) //nolint:gomnd
+
 	s.ArabicPagination = lipgloss.NewStyle().Foreground(subduedColor)
 
 	s.PaginationStyle = lipgloss.NewStyle().PaddingLeft(2) //nolint:gomnd
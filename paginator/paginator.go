@@ -6,8 +6,10 @@ package paginator
 
 import (
 	"fmt"
+	"strconv"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // Type specifies the way we render pagination.
@@ -17,17 +19,37 @@ type Type int
 const (
 	Arabic Type = iota
 	Dots
+	Verbose // "Page 3 of 12"
 )
 
+// Styles contains style definitions for the Verbose pagination type.
+type Styles struct {
+	// Label styles the words "Page" and "of".
+	Label lipgloss.Style
+
+	// Page styles the current page number.
+	Page lipgloss.Style
+
+	// Total styles the total number of pages.
+	Total lipgloss.Style
+}
+
 // Model is the Bubble Tea model for this user interface.
 type Model struct {
-	Type              Type
-	Page              int
-	PerPage           int
-	TotalPages        int
-	ActiveDot         string
-	InactiveDot       string
-	ArabicFormat      string
+	Type         Type
+	Page         int
+	PerPage      int
+	TotalPages   int
+	ActiveDot    string
+	InactiveDot  string
+	ArabicFormat string
+	Styles       Styles
+
+	// Format, if set, renders the pagination in place of the built-in types.
+	// It receives the current page, starting at 1, and the total number of
+	// pages.
+	Format func(page, totalPages int) string
+
 	UsePgUpPgDownKeys bool
 	UseLeftRightKeys  bool
 	UseUpDownKeys     bool
@@ -170,9 +192,15 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 // View renders the pagination to a string.
 func (m Model) View() string {
+	if m.Format != nil {
+		return m.Format(m.Page+1, m.TotalPages)
+	}
+
 	switch m.Type {
 	case Dots:
 		return m.dotsView()
+	case Verbose:
+		return m.verboseView()
 	default:
 		return m.arabicView()
 	}
@@ -194,6 +222,13 @@ func (m Model) arabicView() string {
 	return fmt.Sprintf(m.ArabicFormat, m.Page+1, m.TotalPages)
 }
 
+func (m Model) verboseView() string {
+	return m.Styles.Label.Render("Page ") +
+		m.Styles.Page.Render(strconv.Itoa(m.Page+1)) +
+		m.Styles.Label.Render(" of ") +
+		m.Styles.Total.Render(strconv.Itoa(m.TotalPages))
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -3,6 +3,7 @@ package progress
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -72,6 +73,14 @@ func WithScaledGradient(colorA, colorB string) Option {
 	}
 }
 
+// WithGradientStops sets a gradient fill blending between any number of color
+// stops. See SetGradientStops for details.
+func WithGradientStops(stops ...GradientStop) Option {
+	return func(m *Model) {
+		m.SetGradientStops(stops)
+	}
+}
+
 // WithSolidFill sets the progress to use a solid fill with the given color.
 func WithSolidFill(color string) Option {
 	return func(m *Model) {
@@ -108,6 +117,14 @@ func WithSpringOptions(frequency, damping float64) Option {
 	}
 }
 
+// WithAutoClear clears the progress bar the given duration after it has been
+// completed with Complete.
+func WithAutoClear(d time.Duration) Option {
+	return func(m *Model) {
+		m.ClearDelay = d
+	}
+}
+
 // WithColorProfile sets the color profile to use for the progress bar.
 func WithColorProfile(p termenv.Profile) Option {
 	return func(m *Model) {
@@ -121,6 +138,37 @@ type FrameMsg struct {
 	tag int
 }
 
+// GradientStop defines a color at a given position in a gradient, where At
+// ranges from 0 (the start of the bar) to 1 (the end of the bar).
+type GradientStop struct {
+	At    float64
+	Color lipgloss.Color
+}
+
+// gradientStop is a GradientStop with its color parsed.
+type gradientStop struct {
+	at    float64
+	color colorful.Color
+}
+
+// CompleteMsg is sent when a progress bar completed with Complete has finished
+// animating to 100%.
+type CompleteMsg struct {
+	ID int
+}
+
+// ClearedMsg is sent when a completed progress bar has been cleared. See
+// ClearDelay.
+type ClearedMsg struct {
+	ID int
+}
+
+// clearMsg indicates that a completed progress bar should be cleared.
+type clearMsg struct {
+	id  int
+	tag int
+}
+
 // Model stores values we'll use when rendering the progress bar.
 type Model struct {
 	// An identifier to keep us from receiving messages intended for other
@@ -146,6 +194,10 @@ type Model struct {
 	PercentFormat   string // a fmt string for a float
 	PercentageStyle lipgloss.Style
 
+	// How long to wait after the bar has completed before clearing it. If 0,
+	// the completed bar is left as is.
+	ClearDelay time.Duration
+
 	// Members for animated transitions.
 	spring           harmonica.Spring
 	springCustomized bool
@@ -153,10 +205,15 @@ type Model struct {
 	targetPercent    float64 // percent to which we're animating
 	velocity         float64
 
+	// Completion state.
+	completing bool
+	cleared    bool
+
 	// Gradient settings
 	useRamp    bool
 	rampColorA colorful.Color
 	rampColorB colorful.Color
+	rampStops  []gradientStop
 
 	// When true, we scale the gradient to fit the width of the filled section
 	// of the progress bar. When false, the width of the gradient will be set
@@ -200,6 +257,11 @@ func (m Model) Init() tea.Cmd {
 	return nil
 }
 
+// ID returns the progress bar's unique ID.
+func (m Model) ID() int {
+	return m.id
+}
+
 // Update is used to animation the progress bar during transitions. Use
 // SetPercent to create the command you'll need to trigger the animation.
 //
@@ -214,12 +276,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// If we've more or less reached equilibrium, stop updating.
 		dist := math.Abs(m.percentShown - m.targetPercent)
 		if dist < 0.001 && m.velocity < 0.01 {
+			if m.completing {
+				m.completing = false
+				return m, m.completed()
+			}
 			return m, nil
 		}
 
 		m.percentShown, m.velocity = m.spring.Update(m.percentShown, m.velocity, m.targetPercent)
 		return m, m.nextFrame()
 
+	case clearMsg:
+		if msg.id != m.id || msg.tag != m.tag {
+			return m, nil
+		}
+		m.cleared = true
+		return m, func() tea.Msg {
+			return ClearedMsg{ID: m.id}
+		}
+
 	default:
 		return m, nil
 	}
@@ -247,10 +322,27 @@ func (m Model) Percent() float64 {
 // If you're rendering with ViewAs you won't need this.
 func (m *Model) SetPercent(p float64) tea.Cmd {
 	m.targetPercent = math.Max(0, math.Min(1, p))
+	m.completing = false
+	m.cleared = false
 	m.tag++
 	return m.nextFrame()
 }
 
+// Complete animates the progress bar to 100%. Once the animation has finished
+// a CompleteMsg is sent and, if ClearDelay is set, the bar is cleared after
+// the given delay.
+func (m *Model) Complete() tea.Cmd {
+	cmd := m.SetPercent(1)
+	m.completing = true
+	return cmd
+}
+
+// Cleared returns whether or not the progress bar has been cleared after
+// completing. A cleared progress bar renders as an empty string.
+func (m Model) Cleared() bool {
+	return m.cleared
+}
+
 // IncrPercent increments the percentage by a given amount, returning a command
 // necessary to animate the progress bar to the new percentage.
 //
@@ -270,6 +362,9 @@ func (m *Model) DecrPercent(v float64) tea.Cmd {
 // View renders the an animated progress bar in its current state. To render
 // a static progress bar based on your own calculations use ViewAs instead.
 func (m Model) View() string {
+	if m.cleared {
+		return ""
+	}
 	return m.ViewAs(m.percentShown)
 }
 
@@ -282,12 +377,62 @@ func (m Model) ViewAs(percent float64) string {
 	return b.String()
 }
 
+// ViewAsWidth renders the progress bar with a given percentage at the given
+// width, including the percentage, if shown. The model's Width is unchanged.
+func (m Model) ViewAsWidth(percent float64, width int) string {
+	m.Width = width
+	return m.ViewAs(percent)
+}
+
+// sparkBlocks are the blocks used to render sparklines, from empty to full in
+// eighths.
+var sparkBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// SparklineView renders a compact, single-line progress indicator with the
+// given percentage at the given width, for use where a full bar doesn't fit,
+// such as a table cell. Each cell fills from bottom to top in eighths, and
+// the cells fill from left to right. It uses the same colors as the bar but
+// not the percentage.
+func (m Model) SparklineView(percent float64, width int) string {
+	width = max(1, width)
+	filled := int(math.Round(math.Max(0, math.Min(1, percent)) * float64(width*8)))
+
+	var b strings.Builder
+	for i := 0; i < width; i++ {
+		level := max(0, min(8, filled-i*8))
+		color := m.FullColor
+		if m.useRamp {
+			color = m.rampColor(float64(i) / float64(width)).Hex()
+		}
+		b.WriteString(termenv.
+			String(string(sparkBlocks[level])).
+			Foreground(m.color(color)).
+			String(),
+		)
+	}
+	return b.String()
+}
+
 func (m *Model) nextFrame() tea.Cmd {
 	return tea.Tick(time.Second/time.Duration(fps), func(time.Time) tea.Msg {
 		return FrameMsg{id: m.id, tag: m.tag}
 	})
 }
 
+// completed returns the commands to send once the bar has completed.
+func (m Model) completed() tea.Cmd {
+	id, tag := m.id, m.tag
+	cmds := []tea.Cmd{func() tea.Msg {
+		return CompleteMsg{ID: id}
+	}}
+	if m.ClearDelay > 0 {
+		cmds = append(cmds, tea.Tick(m.ClearDelay, func(time.Time) tea.Msg {
+			return clearMsg{id: id, tag: tag}
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
 func (m Model) barView(b *strings.Builder, percent float64, textWidth int) {
 	var (
 		tw = max(0, m.Width-textWidth)                // total width
@@ -305,7 +450,7 @@ func (m Model) barView(b *strings.Builder, percent float64, textWidth int) {
 			} else {
 				p = float64(i) / float64(tw)
 			}
-			c := m.rampColorA.BlendLuv(m.rampColorB, p).Hex()
+			c := m.rampColor(p).Hex()
 			b.WriteString(termenv.
 				String(string(m.Full)).
 				Foreground(m.color(c)).
@@ -334,6 +479,50 @@ func (m Model) percentageView(percent float64) string {
 	return percentage
 }
 
+// SetGradientStops sets a gradient fill blending between the given color
+// stops, such as green, yellow and then red. Each cell of the bar is colored
+// according to its position along the full width of the bar, so the color of
+// the tip reflects the current value. Stops are sorted by position.
+func (m *Model) SetGradientStops(stops []GradientStop) {
+	m.rampStops = make([]gradientStop, len(stops))
+	for i, s := range stops {
+		// As with setRamp, errors here are only cosmetic so we ignore them.
+		c, _ := colorful.Hex(string(s.Color))
+		m.rampStops[i] = gradientStop{
+			at:    math.Max(0, math.Min(1, s.At)),
+			color: c,
+		}
+	}
+	sort.SliceStable(m.rampStops, func(i, j int) bool {
+		return m.rampStops[i].at < m.rampStops[j].at
+	})
+
+	m.useRamp = true
+	m.scaleRamp = false
+}
+
+// rampColor returns the color of the gradient at the given position.
+func (m Model) rampColor(p float64) colorful.Color {
+	stops := m.rampStops
+	if len(stops) == 0 {
+		return m.rampColorA.BlendLuv(m.rampColorB, p)
+	}
+
+	if p <= stops[0].at {
+		return stops[0].color
+	}
+	for i := 1; i < len(stops); i++ {
+		if p <= stops[i].at {
+			a, b := stops[i-1], stops[i]
+			if b.at == a.at {
+				return b.color
+			}
+			return a.color.BlendLuv(b.color, (p-a.at)/(b.at-a.at))
+		}
+	}
+	return stops[len(stops)-1].color
+}
+
 func (m *Model) setRamp(colorA, colorB string, scaled bool) {
 	// In the event of an error colors here will default to black. For
 	// usability's sake, and because such an error is only cosmetic, we're
@@ -343,6 +532,7 @@ func (m *Model) setRamp(colorA, colorB string, scaled bool) {
 
 	m.useRamp = true
 	m.scaleRamp = scaled
+	m.rampStops = nil
 	m.rampColorA = a
 	m.rampColorB = b
 }
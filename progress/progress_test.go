@@ -0,0 +1,110 @@
+package progress
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// cmdMsgs runs cmd and returns its messages, unpacking batches.
+func cmdMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Slice {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for i := 0; i < v.Len(); i++ {
+		if c, ok := v.Index(i).Interface().(tea.Cmd); ok {
+			msgs = append(msgs, cmdMsgs(c)...)
+		}
+	}
+	return msgs
+}
+
+// animate feeds frames to the model until it stops animating, returning the
+// command returned by the last frame.
+func animate(t *testing.T, m Model) (Model, tea.Cmd) {
+	for i := 0; i < 1000; i++ {
+		next, cmd := m.Update(FrameMsg{id: m.id, tag: m.tag})
+		m = next.(Model)
+		if !m.completing {
+			return m, cmd
+		}
+	}
+	t.Fatal("expected the animation to settle")
+	return m, nil
+}
+
+func TestComplete(t *testing.T) {
+	m := New(WithAutoClear(time.Millisecond))
+	m.Complete()
+	if !m.completing {
+		t.Fatal("expected the bar to be completing")
+	}
+
+	// Frames from before Complete are ignored.
+	next, cmd := m.Update(FrameMsg{id: m.id, tag: m.tag - 1})
+	if m = next.(Model); cmd != nil || m.percentShown != 0 {
+		t.Fatalf("expected a stale frame to be ignored, got %f", m.percentShown)
+	}
+
+	m, cmd = animate(t, m)
+	if m.View() == "" || m.Cleared() {
+		t.Fatal("expected the completed bar to be shown until it's cleared")
+	}
+
+	var clear tea.Msg
+	complete := false
+	for _, msg := range cmdMsgs(cmd) {
+		switch msg := msg.(type) {
+		case CompleteMsg:
+			complete = msg.ID == m.ID()
+		case clearMsg:
+			clear = msg
+		}
+	}
+	if !complete {
+		t.Fatal("expected a CompleteMsg once the animation settled")
+	}
+	if clear == nil {
+		t.Fatal("expected the bar to be cleared after ClearDelay")
+	}
+
+	next, cmd = m.Update(clear)
+	m = next.(Model)
+	if !m.Cleared() || m.View() != "" {
+		t.Fatalf("expected the bar to be cleared, got %q", m.View())
+	}
+	if msg, ok := cmd().(ClearedMsg); !ok || msg.ID != m.ID() {
+		t.Fatalf("expected a ClearedMsg, got %v", msg)
+	}
+}
+
+func TestSetPercentCancelsClear(t *testing.T) {
+	m := New(WithAutoClear(time.Millisecond))
+	m.Complete()
+	m, cmd := animate(t, m)
+
+	var clear tea.Msg
+	for _, msg := range cmdMsgs(cmd) {
+		if msg, ok := msg.(clearMsg); ok {
+			clear = msg
+		}
+	}
+	if clear == nil {
+		t.Fatal("expected the bar to be cleared after ClearDelay")
+	}
+
+	m.SetPercent(0.5)
+	next, cmd := m.Update(clear)
+	m = next.(Model)
+	if cmd != nil || m.Cleared() {
+		t.Fatal("expected SetPercent to cancel the pending clear")
+	}
+}
@@ -1,6 +1,7 @@
 package spinner
 
 import (
+	"strings"
 	"sync"
 	"time"
 
@@ -29,6 +30,16 @@ type Spinner struct {
 	FPS    time.Duration
 }
 
+// width returns the width of the widest frame in cells.
+func (s Spinner) width() (w int) {
+	for _, f := range s.Frames {
+		if fw := lipgloss.Width(f); fw > w {
+			w = fw
+		}
+	}
+	return w
+}
+
 // Some spinners to choose from. You could also make your own.
 var (
 	Line = Spinner{
@@ -101,6 +112,10 @@ type Model struct {
 	frame int
 	id    int
 	tag   int
+
+	// When the current frame was shown, used to catch up on frames that were
+	// missed when ticks are delayed.
+	lastFrame time.Time
 }
 
 // ID returns the spinner's unique ID.
@@ -108,6 +123,20 @@ func (m Model) ID() int {
 	return m.id
 }
 
+// Frame returns the current frame, unstyled and unpadded. If the frame index
+// is out of range an empty string is returned.
+func (m Model) Frame() string {
+	if m.frame >= len(m.Spinner.Frames) {
+		return ""
+	}
+	return m.Spinner.Frames[m.frame]
+}
+
+// FrameIndex returns the index of the current frame.
+func (m Model) FrameIndex() int {
+	return m.frame
+}
+
 // New returns a model with default values.
 func New() Model {
 	return Model{
@@ -145,10 +174,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, nil
 		}
 
-		m.frame++
-		if m.frame >= len(m.Spinner.Frames) {
-			m.frame = 0
-		}
+		m.advance(msg.Time)
 
 		m.tag++
 		return m, m.tick(m.id, m.tag)
@@ -157,13 +183,39 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	}
 }
 
-// View renders the model's view.
+// advance moves to the next frame, skipping frames if more time than a single
+// frame has passed since the last one so that the animation keeps pace with
+// the clock when ticks are delayed.
+func (m *Model) advance(t time.Time) {
+	n := 1
+	if !m.lastFrame.IsZero() && !t.IsZero() && m.Spinner.FPS > 0 {
+		if elapsed := int(t.Sub(m.lastFrame) / m.Spinner.FPS); elapsed > n {
+			n = elapsed
+		}
+	}
+	m.lastFrame = t
+
+	if len(m.Spinner.Frames) == 0 {
+		m.frame = 0
+		return
+	}
+	m.frame = (m.frame + n) % len(m.Spinner.Frames)
+}
+
+// View renders the model's view. Frames are padded to the width of the
+// widest frame so that spinners with frames of varying widths don't cause
+// the surrounding layout to jitter.
 func (m Model) View() string {
 	if m.frame >= len(m.Spinner.Frames) {
 		return "(error)"
 	}
 
-	return m.Style.Render(m.Spinner.Frames[m.frame])
+	frame := m.Spinner.Frames[m.frame]
+	if w := m.Spinner.width(); w > lipgloss.Width(frame) {
+		frame += strings.Repeat(" ", w-lipgloss.Width(frame))
+	}
+
+	return m.Style.Render(frame)
 }
 
 // Tick is the command used to advance the spinner one frame. Use this command
@@ -0,0 +1,47 @@
+package spinner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestViewPadsFramesToConsistentWidth(t *testing.T) {
+	m := New()
+	m.Spinner = Spinner{
+		Frames: []string{"[", "[=", "[==", "[===]"},
+	}
+
+	for i := range m.Spinner.Frames {
+		m.frame = i
+		if w := lipgloss.Width(m.View()); w != 5 {
+			t.Errorf("frame %d: expected width 5, got %d (%q)", i, w, m.View())
+		}
+	}
+}
+
+func TestDelayedTickSkipsFrames(t *testing.T) {
+	m := New()
+	m.Spinner = Spinner{
+		Frames: []string{"a", "b", "c", "d", "e"},
+		FPS:    100 * time.Millisecond,
+	}
+
+	start := time.Now()
+	m, _ = m.Update(TickMsg{Time: start, ID: m.ID()})
+	if m.FrameIndex() != 1 {
+		t.Fatalf("expected frame 1 after the first tick, got %d", m.FrameIndex())
+	}
+
+	m, _ = m.Update(TickMsg{Time: start.Add(100 * time.Millisecond), ID: m.ID()})
+	if m.FrameIndex() != 2 {
+		t.Fatalf("expected frame 2 after an on-time tick, got %d", m.FrameIndex())
+	}
+
+	// A tick 350ms after the last frame should advance three frames.
+	m, _ = m.Update(TickMsg{Time: start.Add(450 * time.Millisecond), ID: m.ID()})
+	if m.FrameIndex() != 0 {
+		t.Fatalf("expected frame 0 after a delayed tick, got %d", m.FrameIndex())
+	}
+}
@@ -0,0 +1,49 @@
+package textinput
+
+import "github.com/charmbracelet/bubbles/key"
+
+// KeyMap defines the keybindings for the text input. The defaults are
+// Emacs-style line editing bindings.
+type KeyMap struct {
+	CharacterForward        key.Binding
+	CharacterBackward       key.Binding
+	WordForward             key.Binding
+	WordBackward            key.Binding
+	DeleteWordBackward      key.Binding
+	DeleteWordForward       key.Binding
+	DeleteAfterCursor       key.Binding
+	DeleteBeforeCursor      key.Binding
+	DeleteCharacterBackward key.Binding
+	DeleteCharacterForward  key.Binding
+	LineStart               key.Binding
+	LineEnd                 key.Binding
+	Paste                   key.Binding
+
+	// TransposeCharacterBackward swaps the character before the cursor with
+	// the character under it, like ctrl+t in readline.
+	TransposeCharacterBackward key.Binding
+
+	// Clear empties the input. It's not bound to any keys by default.
+	Clear key.Binding
+}
+
+// DefaultKeyMap returns the default set of keybindings for the text input.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		CharacterForward:           key.NewBinding(key.WithKeys("right", "ctrl+f")),
+		CharacterBackward:          key.NewBinding(key.WithKeys("left", "ctrl+b")),
+		WordForward:                key.NewBinding(key.WithKeys("alt+right", "alt+f")),
+		WordBackward:               key.NewBinding(key.WithKeys("alt+left", "alt+b")),
+		DeleteWordBackward:         key.NewBinding(key.WithKeys("alt+backspace", "ctrl+w")),
+		DeleteWordForward:          key.NewBinding(key.WithKeys("alt+delete", "alt+d")),
+		DeleteAfterCursor:          key.NewBinding(key.WithKeys("ctrl+k")),
+		DeleteBeforeCursor:         key.NewBinding(key.WithKeys("ctrl+u")),
+		DeleteCharacterBackward:    key.NewBinding(key.WithKeys("backspace")),
+		DeleteCharacterForward:     key.NewBinding(key.WithKeys("delete", "ctrl+d")),
+		TransposeCharacterBackward: key.NewBinding(key.WithKeys("ctrl+t")),
+		LineStart:                  key.NewBinding(key.WithKeys("home", "ctrl+a")),
+		LineEnd:                    key.NewBinding(key.WithKeys("end", "ctrl+e")),
+		Paste:                      key.NewBinding(key.WithKeys("ctrl+v")),
+		Clear:                      key.NewBinding(),
+	}
+}
@@ -8,12 +8,16 @@ import (
 	"unicode"
 
 	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	rw "github.com/mattn/go-runewidth"
 )
 
-const defaultBlinkSpeed = time.Millisecond * 530
+const (
+	defaultBlinkSpeed = time.Millisecond * 530
+	defaultBlinkPause = time.Second
+)
 
 // Internal ID management for text inputs. Necessary for blink integrity when
 // multiple text inputs are involved.
@@ -91,6 +95,38 @@ func (c CursorMode) String() string {
 	}[c]
 }
 
+// OverflowMode describes how a value that's wider than the input's Width is
+// displayed.
+type OverflowMode int
+
+// Available overflow modes.
+const (
+	// OverflowScroll scrolls the value horizontally to follow the cursor.
+	// This is the default behavior.
+	OverflowScroll OverflowMode = iota
+
+	// OverflowAnchorStart always shows the start of the value.
+	OverflowAnchorStart
+
+	// OverflowAnchorEnd always shows the end of the value.
+	OverflowAnchorEnd
+)
+
+// Alignment describes how a value that's narrower than the input's Width is
+// positioned.
+type Alignment int
+
+// Available alignments.
+const (
+	// AlignLeft displays the value at the start of the field. This is the
+	// default behavior.
+	AlignLeft Alignment = iota
+
+	// AlignRight displays the value at the end of the field, such as to line
+	// up amounts.
+	AlignRight
+)
+
 // ValidateFunc is a function that returns an error if the input is invalid.
 type ValidateFunc func(string) error
 
@@ -98,6 +134,9 @@ type ValidateFunc func(string) error
 type Model struct {
 	Err error
 
+	// KeyMap encodes the keybindings recognized by the text input.
+	KeyMap KeyMap
+
 	// General settings.
 	Prompt        string
 	Placeholder   string
@@ -105,6 +144,12 @@ type Model struct {
 	EchoMode      EchoMode
 	EchoCharacter rune
 
+	// BlinkPause is how long the cursor stays solid after a keystroke before
+	// it starts blinking again, so that it doesn't blink while the user is
+	// typing. If it's shorter than BlinkSpeed, BlinkSpeed is used. By default
+	// this is 1 second.
+	BlinkPause time.Duration
+
 	// Styles. These will be applied as inline styles.
 	//
 	// For an introduction to styling with Lip Gloss see:
@@ -114,6 +159,25 @@ type Model struct {
 	BackgroundStyle  lipgloss.Style
 	PlaceholderStyle lipgloss.Style
 	CursorStyle      lipgloss.Style
+	CursorErrStyle   lipgloss.Style // cursor style while Err is set
+	HintStyle        lipgloss.Style
+	ErrStyle         lipgloss.Style
+	TokenStyle       lipgloss.Style
+
+	// CursorGlyph, if set, is drawn in the cursor style at the cursor, such as
+	// '_' or '▏'. Over a character, it's drawn in place of the character,
+	// which shows again when the cursor blinks off, and it's padded to the
+	// width of wide characters so the text doesn't shift. By default the
+	// character under the cursor, or a space, is shown reversed.
+	CursorGlyph rune
+
+	// Hint is an optional message rendered on a second line below the input,
+	// such as "must be a valid email".
+	Hint string
+
+	// ShowErr renders the message of the current validation error, if any,
+	// on the second line in place of the hint.
+	ShowErr bool
 
 	// CharLimit is the maximum amount of characters this input element will
 	// accept. If 0 or less, there's no limit.
@@ -124,6 +188,31 @@ type Model struct {
 	// viewport. If 0 or less this setting is ignored.
 	Width int
 
+	// MaxWidth, if greater than 0, caps the number of characters displayed at
+	// once, so that Width can track the size of a container while the input
+	// stays a comfortable size.
+	MaxWidth int
+
+	// Overflow determines how a value wider than Width is displayed. When
+	// anchored, the cursor is hidden while it's outside of the visible
+	// portion of the value, which is useful for read-only displays.
+	Overflow OverflowMode
+
+	// Align determines where a value narrower than Width is displayed within
+	// the field. It has no effect if Width is 0 or less.
+	Align Alignment
+
+	// ReadOnly prevents the value from being edited by the user while still
+	// allowing the cursor to be moved, such as to display a value in a form.
+	// The value can still be set with SetValue.
+	ReadOnly bool
+
+	// TokenMode turns the input into a token input, such as for entering
+	// tags. Pressing enter or typing a comma commits the text as a token,
+	// and backspace on an empty input removes the last token. Tokens are
+	// rendered with TokenStyle before the text. See Tokens.
+	TokenMode bool
+
 	// The ID of this Model as it relates to other textinput Models.
 	id int
 
@@ -133,6 +222,16 @@ type Model struct {
 	// Underlying text value.
 	value []rune
 
+	// Committed tokens in token mode.
+	tokens []string
+
+	// Input mask, if any. See SetMask.
+	mask []rune
+
+	// Function applied to pasted text before it's inserted, if any. See
+	// SetPasteTransform.
+	pasteTransform func(string) string
+
 	// focus indicates whether user input focus should be on this input
 	// component. When false, ignore keyboard input and hide the cursor.
 	focus bool
@@ -165,10 +264,19 @@ type Model struct {
 func New() Model {
 	return Model{
 		Prompt:           "> ",
+		KeyMap:           DefaultKeyMap(),
 		BlinkSpeed:       defaultBlinkSpeed,
+		BlinkPause:       defaultBlinkPause,
 		EchoCharacter:    '*',
 		CharLimit:        0,
 		PlaceholderStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		HintStyle:        lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		ErrStyle:         lipgloss.NewStyle().Foreground(lipgloss.Color("9")),
+		CursorErrStyle:   lipgloss.NewStyle().Foreground(lipgloss.Color("9")),
+		TokenStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("252")).
+			Background(lipgloss.Color("238")).
+			Padding(0, 1),
 
 		id:         nextID(),
 		value:      nil,
@@ -188,8 +296,13 @@ func New() Model {
 // Deprecated. Use New instead.
 var NewModel = New
 
-// SetValue sets the value of the text input.
+// SetValue sets the value of the text input. If a mask is set, the value is
+// formatted with it.
 func (m *Model) SetValue(s string) {
+	if len(m.mask) > 0 {
+		s = m.applyMask(s)
+	}
+
 	if m.Validate != nil {
 		if err := m.Validate(s); err != nil {
 			m.Err = err
@@ -205,22 +318,166 @@ func (m *Model) SetValue(s string) {
 	} else {
 		m.value = runes
 	}
-	if (m.pos == 0 && len(m.value) == 0) || m.pos > len(m.value) {
+	if (m.pos == 0 && len(m.value) == 0) || m.pos > len(m.value) || len(m.mask) > 0 {
 		m.setCursor(len(m.value))
 	}
 	m.handleOverflow()
 }
 
+// SetPasteTransform sets a function that's applied to pasted text before it's
+// inserted, and before CharLimit is applied, such as to trim whitespace or
+// strip newlines. Pass nil to insert pasted text as is.
+func (m *Model) SetPasteTransform(fn func(string) string) {
+	m.pasteTransform = fn
+}
+
+// maskDigit is the character in a mask that accepts a digit.
+const maskDigit = '#'
+
+// SetMask sets an input mask for formatted values, such as dates or phone
+// numbers. Each # in the mask accepts a digit and any other character is a
+// literal, which is inserted automatically as the value is typed. For
+// example, with the mask "##/##/####", typing "12252022" results in the value
+// "12/25/2022". The rest of the mask is shown after the value as a format
+// hint, or the rest of the Placeholder if it's set, such as "MM/DD/YYYY".
+//
+// In mask mode characters can only be added and removed at the end of the
+// value. Pass an empty string to remove the mask.
+func (m *Model) SetMask(mask string) {
+	raw := m.UnmaskedValue()
+	m.mask = []rune(mask)
+	m.SetValue(raw)
+}
+
+// Mask returns the input mask, if any.
+func (m Model) Mask() string {
+	return string(m.mask)
+}
+
+// UnmaskedValue returns the characters of the value typed into the mask's
+// slots, without the literals. If no mask is set, it returns the value.
+func (m Model) UnmaskedValue() string {
+	if len(m.mask) == 0 {
+		return m.Value()
+	}
+	var raw []rune
+	for i, r := range m.value {
+		if i < len(m.mask) && m.mask[i] == maskDigit {
+			raw = append(raw, r)
+		}
+	}
+	return string(raw)
+}
+
+// applyMask formats the given string with the mask, skipping any characters
+// that don't fit.
+func (m Model) applyMask(s string) string {
+	var value []rune
+	for _, r := range s {
+		value, _ = m.maskAppend(value, r)
+	}
+	return string(value)
+}
+
+// maskAppend appends a character to a masked value, inserting any literals
+// that come before the next slot. It returns whether the character fit.
+func (m Model) maskAppend(value []rune, r rune) ([]rune, bool) {
+	v := value
+	for i := len(value); i < len(m.mask); i++ {
+		c := m.mask[i]
+		if c == maskDigit {
+			if !unicode.IsDigit(r) {
+				return value, false
+			}
+			return append(v, r), true
+		}
+		v = append(v, c)
+		if r == c {
+			return v, true
+		}
+	}
+	return value, false
+}
+
+// maskPop removes the last character typed into a masked value, along with
+// any literals that follow the slot before it.
+func (m Model) maskPop(value []rune) []rune {
+	n := len(value)
+	if n > 0 && m.mask[n-1] == maskDigit {
+		n--
+	}
+	for n > 0 && m.mask[n-1] != maskDigit {
+		n--
+	}
+	return value[:n]
+}
+
+// maskHint returns the part of the mask, or of the placeholder, after the
+// value.
+func (m Model) maskHint() string {
+	if len(m.value) >= len(m.mask) {
+		return ""
+	}
+	if p := []rune(m.Placeholder); len(p) >= len(m.mask) {
+		return string(p[len(m.value):len(m.mask)])
+	}
+	return string(m.mask[len(m.value):])
+}
+
 // Value returns the value of the text input.
 func (m Model) Value() string {
 	return string(m.value)
 }
 
+// Tokens returns the committed tokens. It doesn't include the text currently
+// being entered, which is returned by Value. See TokenMode.
+func (m Model) Tokens() []string {
+	return m.tokens
+}
+
+// SetTokens sets the committed tokens. See TokenMode.
+func (m *Model) SetTokens(tokens []string) {
+	m.tokens = tokens
+}
+
 // Cursor returns the cursor position.
 func (m Model) Cursor() int {
 	return m.pos
 }
 
+// CursorColumn returns the column at which the cursor is rendered, relative to
+// the start of the view. It accounts for the prompt, any tokens, horizontal
+// scrolling, alignment and wide characters, which makes it useful for
+// positioning overlays such as autocomplete suggestions under the cursor.
+func (m Model) CursorColumn() int {
+	col := lipgloss.Width(m.PromptStyle.Render(m.Prompt)) + lipgloss.Width(m.tokensView())
+	if len(m.value) == 0 && m.Placeholder != "" && len(m.mask) == 0 {
+		return col
+	}
+	col += m.alignPadding()
+	if len(m.value) == 0 {
+		return col
+	}
+	start := min(m.offset, m.pos)
+	return col + rw.StringWidth(m.echoTransform(string(m.value[start:m.pos])))
+}
+
+// alignPadding returns the number of columns the value is shifted right by
+// when it's aligned to the right.
+func (m Model) alignPadding() int {
+	width := m.displayWidth()
+	if m.Align != AlignRight || width <= 0 {
+		return 0
+	}
+	valWidth := rw.StringWidth(string(m.value[m.offset:m.offsetRight]))
+	if m.cursorInView() && m.pos >= m.offsetRight {
+		if hint := m.maskHint(); hint != "" {
+			valWidth += rw.StringWidth(hint) - 1
+		}
+	}
+	return max(0, width-valWidth)
+}
+
 // Blink returns whether or not to draw the cursor.
 func (m Model) Blink() bool {
 	return m.blink
@@ -310,16 +567,75 @@ func (m *Model) Blur() {
 	m.blink = true
 }
 
+// Clear empties the input, including any tokens, clears the error and moves
+// the cursor and scroll position back to the start.
+func (m *Model) Clear() {
+	m.Err = nil
+	m.Reset()
+}
+
 // Reset sets the input to its default state with no input. Returns whether
 // or not the cursor blink should reset.
 func (m *Model) Reset() bool {
 	m.value = nil
+	m.tokens = nil
 	return m.setCursor(0)
 }
 
+// handleTokenKey handles the keys that commit and remove tokens in token mode.
+// It returns whether or not the key was handled and whether or not the cursor
+// blink should be reset.
+func (m *Model) handleTokenKey(msg tea.KeyMsg) (handled, resetBlink bool) {
+	switch {
+	case msg.Type == tea.KeyEnter,
+		msg.Type == tea.KeyRunes && !msg.Alt && len(msg.Runes) == 1 && msg.Runes[0] == ',':
+		token := strings.TrimSpace(string(m.value))
+		if token == "" {
+			return true, false
+		}
+		m.tokens = append(m.tokens, token)
+		m.value = nil
+		return true, m.setCursor(0)
+
+	case msg.Type == tea.KeyBackspace && !msg.Alt && len(m.value) == 0 && len(m.tokens) > 0:
+		m.tokens = m.tokens[:len(m.tokens)-1]
+		return true, m.setCursor(0)
+	}
+	return false, false
+}
+
+// handleMaskKey handles editing in mask mode, where characters are only added
+// and removed at the end of the value. It returns whether or not the key was
+// handled and whether or not the cursor blink should be reset.
+func (m *Model) handleMaskKey(msg tea.KeyMsg) (handled, resetBlink bool) {
+	var value []rune
+	switch {
+	case key.Matches(msg, m.KeyMap.Paste, m.KeyMap.Clear):
+		return false, false
+	case key.Matches(msg, m.KeyMap.DeleteCharacterBackward, m.KeyMap.DeleteWordBackward):
+		value = m.maskPop(m.value)
+	case key.Matches(msg, m.KeyMap.DeleteBeforeCursor):
+		value = nil
+	case (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) && !msg.Alt:
+		value = append(value, m.value...)
+		for _, r := range msg.Runes {
+			value, _ = m.maskAppend(value, r)
+		}
+	default:
+		// Moving the cursor and deleting after it don't apply.
+		return true, false
+	}
+
+	m.SetValue(string(value))
+	return true, m.setCursor(len(m.value))
+}
+
 // handle a clipboard paste event, if supported. Returns whether or not the
 // cursor blink should reset.
 func (m *Model) handlePaste(v string) bool {
+	if m.pasteTransform != nil {
+		v = m.pasteTransform(v)
+	}
 	paste := []rune(v)
 
 	var availSpace int
@@ -370,15 +686,48 @@ func (m *Model) handlePaste(v string) bool {
 	return m.setCursor(m.pos)
 }
 
+// displayWidth returns the number of characters that can be displayed at once,
+// taking MaxWidth into account. If 0 or less, there's no limit.
+func (m Model) displayWidth() int {
+	if m.MaxWidth > 0 && (m.Width <= 0 || m.Width > m.MaxWidth) {
+		return m.MaxWidth
+	}
+	return m.Width
+}
+
 // If a max width is defined, perform some logic to treat the visible area
 // as a horizontally scrolling viewport.
 func (m *Model) handleOverflow() {
-	if m.Width <= 0 || rw.StringWidth(string(m.value)) <= m.Width {
+	width := m.displayWidth()
+	if width <= 0 || rw.StringWidth(string(m.value)) <= width {
 		m.offset = 0
 		m.offsetRight = len(m.value)
 		return
 	}
 
+	switch m.Overflow {
+	case OverflowAnchorStart:
+		w, i := 0, 0
+		for i < len(m.value) && w+rw.RuneWidth(m.value[i]) <= width {
+			w += rw.RuneWidth(m.value[i])
+			i++
+		}
+		m.offset = 0
+		m.offsetRight = i
+		return
+
+	case OverflowAnchorEnd:
+		// Leave a cell for the cursor at the end of the value.
+		w, i := 0, len(m.value)
+		for i > 0 && w+rw.RuneWidth(m.value[i-1]) <= width-1 {
+			w += rw.RuneWidth(m.value[i-1])
+			i--
+		}
+		m.offset = i
+		m.offsetRight = len(m.value)
+		return
+	}
+
 	// Correct right offset if we've deleted characters
 	m.offsetRight = min(m.offsetRight, len(m.value))
 
@@ -389,9 +738,9 @@ func (m *Model) handleOverflow() {
 		i := 0
 		runes := m.value[m.offset:]
 
-		for i < len(runes) && w <= m.Width {
+		for i < len(runes) && w <= width {
 			w += rw.RuneWidth(runes[i])
-			if w <= m.Width+1 {
+			if w <= width+1 {
 				i++
 			}
 		}
@@ -404,9 +753,9 @@ func (m *Model) handleOverflow() {
 		runes := m.value[:m.offsetRight]
 		i := len(runes) - 1
 
-		for i > 0 && w < m.Width {
+		for i > 0 && w < width {
 			w += rw.RuneWidth(runes[i])
-			if w <= m.Width {
+			if w <= width {
 				i--
 			}
 		}
@@ -477,6 +826,32 @@ func (m *Model) deleteWordLeft() bool {
 	return blink
 }
 
+// transposeLeft swaps the character before the cursor with the character
+// under it and moves the cursor forward, like ctrl+t in readline. At the end
+// of the value the last two characters are swapped. Returns whether or not
+// the cursor blink should be reset.
+func (m *Model) transposeLeft() bool {
+	pos := m.pos
+	if pos == len(m.value) && pos > 0 {
+		pos = m.clusterStart(pos - 1)
+	}
+	if pos == 0 {
+		return false
+	}
+
+	prev := m.clusterStart(pos - 1)
+	end := m.clusterEnd(pos)
+
+	value := make([]rune, 0, len(m.value))
+	value = append(value, m.value[:prev]...)
+	value = append(value, m.value[pos:end]...)
+	value = append(value, m.value[prev:pos]...)
+	value = append(value, m.value[end:]...)
+	m.value = value
+
+	return m.setCursor(end)
+}
+
 // deleteWordRight deletes the word right to the cursor. Returns whether or not
 // the cursor blink should be reset. If input is masked delete everything after
 // the cursor so as not to reveal word breaks in the masked input.
@@ -610,68 +985,69 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyBackspace: // delete character before cursor
-			m.Err = nil
+		if m.ReadOnly && m.isEditKey(msg) {
+			break
+		}
 
-			if msg.Alt {
-				resetBlink = m.deleteWordLeft()
-			} else {
-				if len(m.value) > 0 {
-					m.value = append(m.value[:max(0, m.pos-1)], m.value[m.pos:]...)
-					if m.pos > 0 {
-						resetBlink = m.setCursor(m.pos - 1)
-					}
-				}
+		if m.TokenMode {
+			if handled, blink := m.handleTokenKey(msg); handled {
+				resetBlink = blink
+				break
 			}
-		case tea.KeyLeft, tea.KeyCtrlB:
-			if msg.Alt { // alt+left arrow, back one word
-				resetBlink = m.wordLeft()
+		}
+
+		if len(m.mask) > 0 {
+			if handled, blink := m.handleMaskKey(msg); handled {
+				resetBlink = blink
 				break
 			}
-			if m.pos > 0 { // left arrow, ^F, back one character
-				resetBlink = m.setCursor(m.pos - 1)
+		}
+
+		switch {
+		case key.Matches(msg, m.KeyMap.DeleteWordBackward):
+			m.Err = nil
+			resetBlink = m.deleteWordLeft()
+		case key.Matches(msg, m.KeyMap.DeleteCharacterBackward):
+			m.Err = nil
+			if len(m.value) > 0 && m.pos > 0 {
+				start := m.clusterStart(m.pos - 1)
+				m.value = append(m.value[:start], m.value[m.pos:]...)
+				resetBlink = m.setCursor(start)
 			}
-		case tea.KeyRight, tea.KeyCtrlF:
-			if msg.Alt { // alt+right arrow, forward one word
-				resetBlink = m.wordRight()
-				break
+		case key.Matches(msg, m.KeyMap.WordBackward):
+			resetBlink = m.wordLeft()
+		case key.Matches(msg, m.KeyMap.CharacterBackward):
+			if m.pos > 0 {
+				resetBlink = m.setCursor(m.clusterStart(m.pos - 1))
 			}
-			if m.pos < len(m.value) { // right arrow, ^F, forward one character
-				resetBlink = m.setCursor(m.pos + 1)
+		case key.Matches(msg, m.KeyMap.WordForward):
+			resetBlink = m.wordRight()
+		case key.Matches(msg, m.KeyMap.CharacterForward):
+			if m.pos < len(m.value) {
+				resetBlink = m.setCursor(m.clusterEnd(m.pos))
 			}
-		case tea.KeyCtrlW: // ^W, delete word left of cursor
-			resetBlink = m.deleteWordLeft()
-		case tea.KeyHome, tea.KeyCtrlA: // ^A, go to beginning
+		case key.Matches(msg, m.KeyMap.LineStart):
 			resetBlink = m.cursorStart()
-		case tea.KeyDelete, tea.KeyCtrlD: // ^D, delete char under cursor
+		case key.Matches(msg, m.KeyMap.LineEnd):
+			resetBlink = m.cursorEnd()
+		case key.Matches(msg, m.KeyMap.DeleteCharacterForward):
 			if len(m.value) > 0 && m.pos < len(m.value) {
-				m.value = append(m.value[:m.pos], m.value[m.pos+1:]...)
+				m.value = append(m.value[:m.pos], m.value[m.clusterEnd(m.pos):]...)
 			}
-		case tea.KeyCtrlE, tea.KeyEnd: // ^E, go to end
-			resetBlink = m.cursorEnd()
-		case tea.KeyCtrlK: // ^K, kill text after cursor
+		case key.Matches(msg, m.KeyMap.DeleteWordForward):
+			resetBlink = m.deleteWordRight()
+		case key.Matches(msg, m.KeyMap.DeleteAfterCursor):
 			resetBlink = m.deleteAfterCursor()
-		case tea.KeyCtrlU: // ^U, kill text before cursor
+		case key.Matches(msg, m.KeyMap.DeleteBeforeCursor):
 			resetBlink = m.deleteBeforeCursor()
-		case tea.KeyCtrlV: // ^V paste
+		case key.Matches(msg, m.KeyMap.TransposeCharacterBackward):
+			resetBlink = m.transposeLeft()
+		case key.Matches(msg, m.KeyMap.Clear):
+			m.Clear()
+			resetBlink = m.cursorMode == CursorBlink
+		case key.Matches(msg, m.KeyMap.Paste):
 			return m, Paste
-		case tea.KeyRunes, tea.KeySpace: // input regular characters
-			if msg.Alt && len(msg.Runes) == 1 {
-				if msg.Runes[0] == 'd' { // alt+d, delete word right of cursor
-					resetBlink = m.deleteWordRight()
-					break
-				}
-				if msg.Runes[0] == 'b' { // alt+b, back one word
-					resetBlink = m.wordLeft()
-					break
-				}
-				if msg.Runes[0] == 'f' { // alt+f, forward one word
-					resetBlink = m.wordRight()
-					break
-				}
-			}
-
+		case msg.Type == tea.KeyRunes, msg.Type == tea.KeySpace:
 			// Input a regular character
 			if m.CharLimit <= 0 || len(m.value) < m.CharLimit {
 				runes := msg.Runes
@@ -721,7 +1097,13 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		return m, nil
 
 	case pasteMsg:
+		if m.ReadOnly {
+			break
+		}
 		resetBlink = m.handlePaste(string(msg))
+		if len(m.mask) > 0 {
+			m.setCursor(len(m.value))
+		}
 
 	case pasteErrMsg:
 		m.Err = msg
@@ -729,17 +1111,39 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	var cmd tea.Cmd
 	if resetBlink {
-		cmd = m.blinkCmd()
+		d := m.BlinkSpeed
+		if m.BlinkPause > d {
+			d = m.BlinkPause
+		}
+		cmd = m.blinkCmdAfter(d)
 	}
 
 	m.handleOverflow()
 	return m, cmd
 }
 
+// Height returns the number of lines the textinput renders, which is two when
+// a hint or error message is showing and one otherwise.
+func (m Model) Height() int {
+	if m.hintView() != "" {
+		return 2
+	}
+	return 1
+}
+
 // View renders the textinput in its current state.
 func (m Model) View() string {
+	v := m.inputView()
+	if hint := m.hintView(); hint != "" {
+		v += "\n" + hint
+	}
+	return v
+}
+
+// inputView renders the input line.
+func (m Model) inputView() string {
 	// Placeholder text
-	if len(m.value) == 0 && m.Placeholder != "" {
+	if len(m.value) == 0 && m.Placeholder != "" && len(m.mask) == 0 {
 		return m.placeholderView()
 	}
 
@@ -747,27 +1151,51 @@ func (m Model) View() string {
 
 	value := m.value[m.offset:m.offsetRight]
 	pos := max(0, m.pos-m.offset)
-	v := styleText(m.echoTransform(string(value[:pos])))
+	cursorInView := m.cursorInView()
 
-	if pos < len(value) {
-		v += m.cursorView(m.echoTransform(string(value[pos]))) // cursor and text under it
-		v += styleText(m.echoTransform(string(value[pos+1:]))) // text after cursor
+	var (
+		v         string
+		hintWidth int
+	)
+	if !cursorInView {
+		v = styleText(m.echoTransform(string(value)))
+	} else if pos < len(value) {
+		end := min(len(value), m.clusterEnd(m.pos)-m.offset)
+		v = styleText(m.echoTransform(string(value[:pos])))
+		v += m.charCursorView(m.echoTransform(string(value[pos:end]))) // cursor and text under it
+		v += styleText(m.echoTransform(string(value[end:])))           // text after cursor
+	} else if hint := m.maskHint(); hint != "" {
+		// In mask mode the rest of the mask is shown after the value, with
+		// the cursor on its first character.
+		v = styleText(m.echoTransform(string(value)))
+		v += m.maskHintView(hint)
+		hintWidth = rw.StringWidth(hint) - 1
 	} else {
-		v += m.cursorView(" ")
+		v = styleText(m.echoTransform(string(value)))
+		v += m.endCursorView()
 	}
 
 	// If a max width and background color were set fill the empty spaces with
 	// the background color.
-	valWidth := rw.StringWidth(string(value))
-	if m.Width > 0 && valWidth <= m.Width {
-		padding := max(0, m.Width-valWidth)
-		if valWidth+padding <= m.Width && pos < len(value) {
-			padding++
+	valWidth := rw.StringWidth(string(value)) + hintWidth
+	if width := m.displayWidth(); width > 0 && valWidth <= width {
+		padding := max(0, width-valWidth)
+		if m.Align == AlignRight {
+			// Keep the end of the value in place whether or not the cursor
+			// is after it.
+			v = styleText(strings.Repeat(" ", m.alignPadding())) + v
+			if pos < len(value) || !cursorInView {
+				v += styleText(" ")
+			}
+		} else {
+			if valWidth+padding <= width && (pos < len(value) || !cursorInView) {
+				padding++
+			}
+			v += styleText(strings.Repeat(" ", padding))
 		}
-		v += styleText(strings.Repeat(" ", padding))
 	}
 
-	return m.PromptStyle.Render(m.Prompt) + v
+	return m.PromptStyle.Render(m.Prompt) + m.tokensView() + v
 }
 
 // placeholderView returns the prompt and placeholder view, if any.
@@ -788,7 +1216,77 @@ func (m Model) placeholderView() string {
 	// The rest of the placeholder text
 	v += style(p[1:])
 
-	return m.PromptStyle.Render(m.Prompt) + v
+	return m.PromptStyle.Render(m.Prompt) + m.tokensView() + v
+}
+
+// maskHintView renders the rest of the mask with the cursor on its first
+// character, like the placeholder.
+func (m Model) maskHintView(hint string) string {
+	var (
+		v     string
+		h     = []rune(hint)
+		style = m.PlaceholderStyle.Inline(true).Render
+	)
+
+	if m.blink {
+		v += m.cursorView(style(string(h[:1])))
+	} else {
+		v += m.cursorView(string(h[:1]))
+	}
+	return v + style(string(h[1:]))
+}
+
+// tokensView renders the committed tokens, if any.
+func (m Model) tokensView() string {
+	var b strings.Builder
+	for _, t := range m.tokens {
+		b.WriteString(m.TokenStyle.Inline(true).Render(t))
+		b.WriteString(" ")
+	}
+	return b.String()
+}
+
+// clusterStart returns the index of the first rune of the character that the
+// rune at index i belongs to, so that combining marks stay attached to the
+// rune they modify.
+func (m Model) clusterStart(i int) int {
+	for i > 0 && isCombining(m.value[i]) {
+		i--
+	}
+	return i
+}
+
+// clusterEnd returns the index just past the character that begins at index
+// i, including any combining marks that follow it.
+func (m Model) clusterEnd(i int) int {
+	i++
+	for i < len(m.value) && isCombining(m.value[i]) {
+		i++
+	}
+	return min(i, len(m.value))
+}
+
+// cursorInView returns whether or not the cursor is within the visible portion
+// of the value. This is only ever false when the overflow is anchored.
+func (m Model) cursorInView() bool {
+	if m.Overflow == OverflowScroll {
+		return true
+	}
+	if m.pos < m.offset {
+		return false
+	}
+	return m.pos < m.offsetRight || (m.pos == len(m.value) && m.offsetRight == len(m.value))
+}
+
+// hintView renders the hint or error message, if any.
+func (m Model) hintView() string {
+	if m.ShowErr && m.Err != nil {
+		return m.ErrStyle.Inline(true).Render(m.Err.Error())
+	}
+	if m.Hint != "" {
+		return m.HintStyle.Inline(true).Render(m.Hint)
+	}
+	return ""
 }
 
 // cursorView styles the cursor.
@@ -796,11 +1294,46 @@ func (m Model) cursorView(v string) string {
 	if m.blink {
 		return m.TextStyle.Render(v)
 	}
-	return m.CursorStyle.Inline(true).Reverse(true).Render(v)
+	return m.cursorStyle().Inline(true).Reverse(true).Render(v)
+}
+
+// charCursorView renders the cursor over the given character of the value,
+// using CursorGlyph if it's set.
+func (m Model) charCursorView(v string) string {
+	if m.CursorGlyph == 0 {
+		return m.cursorView(v)
+	}
+	if m.blink {
+		return m.TextStyle.Render(v)
+	}
+	glyph := m.cursorStyle().Inline(true).Render(string(m.CursorGlyph))
+	if pad := rw.StringWidth(v) - rw.RuneWidth(m.CursorGlyph); pad > 0 {
+		glyph += m.TextStyle.Render(strings.Repeat(" ", pad))
+	}
+	return glyph
+}
+
+// endCursorView renders the cursor when it's at the end of the value, using
+// CursorGlyph if it's set.
+func (m Model) endCursorView() string {
+	return m.charCursorView(" ")
+}
+
+// cursorStyle returns the style for the cursor in its current state.
+func (m Model) cursorStyle() lipgloss.Style {
+	if m.Err != nil {
+		return m.CursorErrStyle
+	}
+	return m.CursorStyle
 }
 
 // blinkCmd is an internal command used to manage cursor blinking.
 func (m *Model) blinkCmd() tea.Cmd {
+	return m.blinkCmdAfter(m.BlinkSpeed)
+}
+
+// blinkCmdAfter is like blinkCmd, but blinks after the given delay.
+func (m *Model) blinkCmdAfter(d time.Duration) tea.Cmd {
 	if m.cursorMode != CursorBlink {
 		return nil
 	}
@@ -809,7 +1342,7 @@ func (m *Model) blinkCmd() tea.Cmd {
 		m.blinkCtx.cancel()
 	}
 
-	ctx, cancel := context.WithTimeout(m.blinkCtx.ctx, m.BlinkSpeed)
+	ctx, cancel := context.WithTimeout(m.blinkCtx.ctx, d)
 	m.blinkCtx.cancel = cancel
 
 	m.blinkTag++
@@ -838,6 +1371,28 @@ func Paste() tea.Msg {
 	return pasteMsg(str)
 }
 
+// isEditKey returns whether or not the given key edits the value, as opposed to
+// moving the cursor.
+func (m Model) isEditKey(msg tea.KeyMsg) bool {
+	k := m.KeyMap
+	switch {
+	case key.Matches(msg, k.CharacterForward, k.CharacterBackward, k.WordForward,
+		k.WordBackward, k.LineStart, k.LineEnd):
+		return false
+	case key.Matches(msg, k.DeleteWordBackward, k.DeleteWordForward,
+		k.DeleteAfterCursor, k.DeleteBeforeCursor, k.DeleteCharacterBackward,
+		k.DeleteCharacterForward, k.TransposeCharacterBackward, k.Paste, k.Clear):
+		return true
+	}
+	return msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace || msg.Type == tea.KeyEnter
+}
+
+// isCombining returns whether the given rune is a combining mark, which is
+// rendered together with the rune before it.
+func isCombining(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me)
+}
+
 func clamp(v, low, high int) int {
 	if high < low {
 		low, high = high, low
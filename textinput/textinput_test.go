@@ -0,0 +1,337 @@
+package textinput
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	rw "github.com/mattn/go-runewidth"
+)
+
+func TestCombiningMarksAttachToBase(t *testing.T) {
+	m := New()
+	m.Focus()
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'\u0301'}})
+
+	if m.Value() != "e\u0301" {
+		t.Fatalf("expected value %q, got %q", "e\u0301", m.Value())
+	}
+	if w := rw.StringWidth(m.Value()); w != 1 {
+		t.Errorf("expected value to occupy 1 cell, got %d", w)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if m.Cursor() != 0 {
+		t.Errorf("expected cursor to move before the accented character, got %d", m.Cursor())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.Value() != "" {
+		t.Errorf("expected backspace to delete the accented character, got %q", m.Value())
+	}
+}
+
+func TestTokenMode(t *testing.T) {
+	m := New()
+	m.TokenMode = true
+	m.Focus()
+
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("foo")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune("bar")},
+		{Type: tea.KeyRunes, Runes: []rune{','}},
+		{Type: tea.KeyRunes, Runes: []rune("baz")},
+	} {
+		m, _ = m.Update(msg)
+	}
+
+	if got := m.Tokens(); len(got) != 2 || got[0] != "foo" || got[1] != "bar" {
+		t.Fatalf("expected tokens [foo bar], got %v", got)
+	}
+	if m.Value() != "baz" {
+		t.Fatalf("expected value %q, got %q", "baz", m.Value())
+	}
+
+	for i := 0; i < 4; i++ {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	if got := m.Tokens(); len(got) != 1 || got[0] != "foo" {
+		t.Fatalf("expected backspace on empty input to remove the last token, got %v", got)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	m := New()
+	m.ReadOnly = true
+	m.SetValue("foo")
+	m.Focus()
+
+	for _, msg := range []tea.Msg{
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("bar")},
+		tea.KeyMsg{Type: tea.KeyBackspace},
+		tea.KeyMsg{Type: tea.KeyCtrlU},
+		pasteMsg("baz"),
+	} {
+		m, _ = m.Update(msg)
+	}
+	if m.Value() != "foo" {
+		t.Fatalf("expected value to be unchanged, got %q", m.Value())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyHome})
+	if m.Cursor() != 0 {
+		t.Fatalf("expected cursor to move to the start, got %d", m.Cursor())
+	}
+}
+
+func TestCursorColumn(t *testing.T) {
+	m := New()
+	m.Prompt = "> "
+	m.SetValue("日本語")
+	m.SetCursor(2)
+
+	if col := m.CursorColumn(); col != 6 {
+		t.Fatalf("expected cursor column 6, got %d", col)
+	}
+}
+
+func TestClearKey(t *testing.T) {
+	m := New()
+	m.KeyMap.Clear = key.NewBinding(key.WithKeys("ctrl+l"))
+	m.SetValue("foo bar")
+	m.Focus()
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	if m.Value() != "" {
+		t.Fatalf("expected ctrl+k to delete to the end, got %q", m.Value())
+	}
+
+	m.SetValue("foo bar")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	if m.Value() != "" || m.Cursor() != 0 {
+		t.Fatalf("expected input to be cleared, got %q at %d", m.Value(), m.Cursor())
+	}
+}
+
+func TestMaxWidth(t *testing.T) {
+	m := New()
+	m.Prompt = ""
+	m.Width = 20
+	m.MaxWidth = 5
+	m.Focus()
+	m.SetValue("abcdefghij")
+
+	if w := lipgloss.Width(m.View()); w != 6 {
+		t.Fatalf("expected view to be 6 cells wide, got %d", w)
+	}
+}
+
+func TestMask(t *testing.T) {
+	m := New()
+	m.SetMask("##/##/####")
+	m.Focus()
+
+	for _, msg := range []tea.Msg{
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("12x2")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")},
+		tea.KeyMsg{Type: tea.KeyBackspace},
+		tea.KeyMsg{Type: tea.KeyLeft},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("52022")},
+	} {
+		m, _ = m.Update(msg)
+	}
+	if m.Value() != "12/25/2022" {
+		t.Fatalf("expected formatted value, got %q", m.Value())
+	}
+	if m.UnmaskedValue() != "12252022" {
+		t.Fatalf("expected unmasked value, got %q", m.UnmaskedValue())
+	}
+
+	m.SetValue("0101")
+	if m.Value() != "01/01" || m.Cursor() != 5 {
+		t.Fatalf("expected value to be masked with the cursor at the end, got %q at %d", m.Value(), m.Cursor())
+	}
+	m.Placeholder = "MM/DD/YYYY"
+	if v := m.View(); !strings.Contains(v, "YYYY") {
+		t.Fatalf("expected the rest of the placeholder to be shown, got %q", v)
+	}
+}
+
+func TestAlignRight(t *testing.T) {
+	m := New()
+	m.Prompt = ""
+	m.Width = 6
+	m.Align = AlignRight
+	m.Focus()
+	m.SetValue("1.50")
+
+	end := m.View()
+	m.SetCursor(0)
+	start := m.View()
+
+	for _, v := range []string{end, start} {
+		if w := lipgloss.Width(v); w != 7 {
+			t.Fatalf("expected view to be 7 cells wide, got %d", w)
+		}
+	}
+	if !strings.HasPrefix(end, "  ") || strings.Index(end, "0") != strings.Index(start, "0") {
+		t.Fatalf("expected value to be right-aligned in place, got %q and %q", end, start)
+	}
+}
+
+func TestPasteTransform(t *testing.T) {
+	m := New()
+	m.CharLimit = 5
+	m.SetPasteTransform(strings.TrimSpace)
+	m.Focus()
+
+	m, _ = m.Update(pasteMsg("  abcde\n"))
+	if m.Value() != "abcde" {
+		t.Fatalf("expected pasted text to be trimmed, got %q", m.Value())
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	m := New()
+	m.Focus()
+	m.SetValue("abcd")
+
+	m.SetCursor(1)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m.Value() != "bacd" || m.Cursor() != 2 {
+		t.Fatalf("expected \"bacd\" with the cursor at 2, got %q at %d", m.Value(), m.Cursor())
+	}
+
+	m.CursorEnd()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m.Value() != "badc" || m.Cursor() != 4 {
+		t.Fatalf("expected \"badc\" with the cursor at the end, got %q at %d", m.Value(), m.Cursor())
+	}
+
+	m.CursorStart()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m.Value() != "badc" {
+		t.Fatalf("expected no change at the start, got %q", m.Value())
+	}
+}
+
+func TestCursorColumnAlignRight(t *testing.T) {
+	m := New()
+	m.Prompt = ""
+	m.Width = 6
+	m.Align = AlignRight
+	m.Focus()
+	m.SetValue("1.50")
+	m.CursorEnd()
+
+	if c := m.CursorColumn(); c != 6 {
+		t.Fatalf("expected cursor at column 6, got %d", c)
+	}
+	m.SetCursor(1)
+	if c := m.CursorColumn(); c != 3 {
+		t.Fatalf("expected cursor at column 3, got %d", c)
+	}
+	v := m.View()
+	if c := lipgloss.Width(v[:strings.Index(v, ".")]); c != 3 {
+		t.Fatalf("expected the character under the cursor at column 3, got %d", c)
+	}
+}
+
+func TestCursorGlyph(t *testing.T) {
+	m := New()
+	m.Prompt = ""
+	m.CursorGlyph = '_'
+	m.Focus()
+	m.SetValue("ab界")
+	m.SetCursorMode(CursorStatic)
+
+	m.SetCursor(0)
+	if v := m.View(); !strings.HasPrefix(v, "_") || strings.Contains(v, "a") {
+		t.Fatalf("expected the glyph in place of the character under the cursor, got %q", v)
+	}
+
+	m.SetCursor(2)
+	if v := m.View(); !strings.Contains(v, "ab_ ") || lipgloss.Width(v) != lipgloss.Width("ab界") {
+		t.Fatalf("expected the glyph padded to the width of a wide character, got %q", v)
+	}
+
+	m.CursorEnd()
+	if v := m.View(); !strings.HasSuffix(v, "ab界_") {
+		t.Fatalf("expected the glyph at the end of the value, got %q", v)
+	}
+}
+
+func TestBlinkPause(t *testing.T) {
+	m := New()
+	m.BlinkSpeed = time.Millisecond
+	m.BlinkPause = 50 * time.Millisecond
+	m.Focus()
+
+	start := time.Now()
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if m.Blink() {
+		t.Fatal("expected the cursor to be shown after a keystroke")
+	}
+	if cmd == nil {
+		t.Fatal("expected a command to resume blinking")
+	}
+
+	// Blinks scheduled before the keystroke are ignored.
+	m, _ = m.Update(blinkMsg{id: m.id, tag: m.blinkTag - 1})
+	if m.Blink() {
+		t.Fatal("expected a stale blink to leave the cursor shown")
+	}
+
+	msg := cmd()
+	if elapsed := time.Since(start); elapsed < m.BlinkPause {
+		t.Fatalf("expected the cursor to stay solid for %s, resumed after %s", m.BlinkPause, elapsed)
+	}
+	m, _ = m.Update(msg)
+	if !m.Blink() {
+		t.Fatal("expected blinking to resume after the pause")
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	newInput := func(mode OverflowMode) Model {
+		m := New()
+		m.Prompt = ""
+		m.Width = 5
+		m.Overflow = mode
+		m.CursorGlyph = '_'
+		m.Focus()
+		m.SetCursorMode(CursorStatic)
+		m.SetValue("abcdefghij")
+		m.CursorEnd()
+		return m
+	}
+
+	tests := []struct {
+		name   string
+		mode   OverflowMode
+		cursor int
+		want   string
+	}{
+		{"scroll at end", OverflowScroll, 10, "fghij_"},
+		{"scroll at start", OverflowScroll, 0, "_bcdef"},
+		{"anchor start with cursor in view", OverflowAnchorStart, 2, "ab_de"},
+		{"anchor start with cursor hidden", OverflowAnchorStart, 10, "abcde"},
+		{"anchor end with cursor in view", OverflowAnchorEnd, 10, "ghij_"},
+		{"anchor end with cursor hidden", OverflowAnchorEnd, 0, "ghij"},
+	}
+	for _, tt := range tests {
+		m := newInput(tt.mode)
+		m.SetCursor(tt.cursor)
+		if v := strings.TrimRight(m.View(), " "); v != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, v)
+		}
+	}
+}
@@ -15,6 +15,14 @@ type KeyMap struct {
 	HalfPageDown key.Binding
 	Down         key.Binding
 	Up           key.Binding
+
+	// Scroll to the next and previous highlighted lines. See
+	// Model.SetHighlightedLines.
+	NextHighlight key.Binding
+	PrevHighlight key.Binding
+
+	// Collapse or expand the first fold in view. See Model.AddFold.
+	ToggleFold key.Binding
 }
 
 // DefaultKeyMap returns a set of pager-like default keybindings.
@@ -44,5 +52,17 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("down", "j"),
 			key.WithHelp("↓/j", "down"),
 		),
+		NextHighlight: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next highlight"),
+		),
+		PrevHighlight: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "prev highlight"),
+		),
+		ToggleFold: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "toggle fold"),
+		),
 	}
 }
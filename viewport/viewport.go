@@ -2,11 +2,32 @@ package viewport
 
 import (
 	"math"
+	"sort"
 	"strings"
+	"sync/atomic"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/reflow/ansi"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/reflow/wrap"
+)
+
+// WrapMode determines where lines are broken when soft wrapping is enabled.
+type WrapMode int
+
+// Available wrap modes.
+const (
+	// WordWrap breaks lines at word boundaries. Words that are too long to
+	// fit on a line are broken at the last character that fits.
+	WordWrap WrapMode = iota
+
+	// CharWrap breaks lines at the last character that fits, regardless of
+	// word boundaries.
+	CharWrap
 )
 
 // New returns a new model with the given width and height as well as default
@@ -18,6 +39,17 @@ func New(width, height int) (m Model) {
 	return m
 }
 
+// ReachedTopMsg is sent by Update when scrolling moves the viewport to the top
+// of the content, such as to load earlier content. It's also sent when
+// scrolling up while already at the top, so that it can be used to load more
+// content on demand.
+type ReachedTopMsg struct{}
+
+// ReachedBottomMsg is sent by Update when scrolling moves the viewport to the
+// bottom of the content, or when scrolling down while already at the bottom,
+// such as to load more content.
+type ReachedBottomMsg struct{}
+
 // Model is the Bubble Tea model for this viewport element.
 type Model struct {
 	Width  int
@@ -52,14 +84,111 @@ type Model struct {
 	// which is usually via the alternate screen buffer.
 	HighPerformanceRendering bool
 
+	// TabWidth, if greater than 0, expands tabs to spaces at tab stops of
+	// the given width when rendering. The content itself is left unchanged.
+	// If 0, tabs are rendered as-is.
+	TabWidth int
+
+	// SoftWrap, if true, wraps lines that are wider than the viewport onto
+	// multiple lines instead of letting them overflow. Scrolling, line counts
+	// and search all operate on the wrapped lines.
+	SoftWrap bool
+
+	// WrapMode determines where lines are broken when SoftWrap is enabled. By
+	// default, this is WordWrap.
+	WrapMode WrapMode
+
+	// BottomAnchored, if true, keeps the viewport scrolled to the bottom as
+	// content is set or appended, like a chat or log, unless it's been
+	// scrolled away from the bottom. See ScrollToBottom.
+	BottomAnchored bool
+
+	// SearchMatchStyle is applied to matches of the search term. See Search.
+	SearchMatchStyle lipgloss.Style
+
+	// CurrentMatchStyle is applied to the match most recently navigated to
+	// with NextMatch or PrevMatch.
+	CurrentMatchStyle lipgloss.Style
+
+	// FillCharacter, if set, is rendered on each line below the end of the
+	// content, like the tildes in vim. It's styled with FillStyle.
+	FillCharacter rune
+	FillStyle     lipgloss.Style
+
+	// ColumnGuideStyle is applied to the cells at the column guides. See
+	// SetColumnGuides.
+	ColumnGuideStyle lipgloss.Style
+
+	// HighlightStyle is applied to highlighted lines. See SetHighlightedLines.
+	HighlightStyle lipgloss.Style
+
+	// FoldStyle is applied to the summary lines of collapsed folds. See
+	// AddFold.
+	FoldStyle lipgloss.Style
+
+	// LeftMarginStyle is applied to the left margin, such as to give it a
+	// background color. See SetLeftMargin.
+	LeftMarginStyle lipgloss.Style
+
 	initialized bool
 	lines       []string
+
+	// Whether key handling has been disabled. See SetKeyHandlingEnabled.
+	keysDisabled bool
+
+	// Number of columns to indent the content by. See SetLeftMargin.
+	leftMargin int
+
+	// Folded regions of the content in ascending order, and the content lines
+	// with the collapsed folds replaced by their summaries.
+	folds  []fold
+	folded []string
+
+	// Content passed to SetContent that hasn't been split into lines yet.
+	// It's processed on the next Update, so rapid calls to SetContent only
+	// cost one split and wrap per frame.
+	pending    string
+	hasPending bool
+
+	// Zero-based columns at which to draw guides, in ascending order.
+	columnGuides []int
+
+	// Highlighted lines in ascending order, and the one most recently
+	// navigated to with NextHighlight or PrevHighlight, or -1.
+	highlights       []int
+	currentHighlight int
+
+	// Fixed regions at the top and bottom of the viewport that don't scroll.
+	stickyTop    []string
+	stickyBottom []string
+
+	// The lines as they're displayed, computed once per version of the
+	// content and settings. See displayCache.
+	version uint64
+	display *displayCache
+
+	// Search state. The current match is identified by its line and the byte
+	// offset of the match within the text of that line, without any escape
+	// sequences.
+	searchTerm string
+	matchLine  int
+	matchCol   int
 }
 
 func (m *Model) setInitialValues() {
 	m.KeyMap = DefaultKeyMap()
 	m.MouseWheelEnabled = true
 	m.MouseWheelDelta = 3
+	m.SearchMatchStyle = lipgloss.NewStyle().Reverse(true)
+	m.CurrentMatchStyle = lipgloss.NewStyle().
+		Background(lipgloss.Color("205")).
+		Foreground(lipgloss.Color("0"))
+	m.ColumnGuideStyle = lipgloss.NewStyle().Background(lipgloss.Color("236"))
+	m.HighlightStyle = lipgloss.NewStyle().Background(lipgloss.Color("52"))
+	m.FoldStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	m.matchLine = -1
+	m.currentHighlight = -1
+	m.display = &displayCache{}
 	m.initialized = true
 }
 
@@ -87,56 +216,765 @@ func (m Model) PastBottom() bool {
 
 // ScrollPercent returns the amount scrolled as a float between 0 and 1.
 func (m Model) ScrollPercent() float64 {
-	if m.Height >= len(m.lines) {
+	lines := m.displayLines()
+	if m.scrollHeight() >= len(lines) {
 		return 1.0
 	}
 	y := float64(m.YOffset)
-	h := float64(m.Height)
-	t := float64(len(m.lines) - 1)
+	h := float64(m.scrollHeight())
+	t := float64(len(lines) - 1)
 	v := y / (t - h)
 	return math.Max(0.0, math.Min(1.0, v))
 }
 
+// TotalLineCount returns the total number of lines in the content. When
+// SoftWrap is enabled, this is the number of wrapped lines.
+func (m Model) TotalLineCount() int {
+	return len(m.displayLines())
+}
+
+// VisibleLineCount returns the number of lines of content currently visible in
+// the viewport.
+func (m Model) VisibleLineCount() int {
+	top := clamp(m.YOffset, 0, m.maxYOffset())
+	bottom := min(top+m.scrollHeight(), len(m.displayLines()))
+	return max(0, bottom-top)
+}
+
+// ContentFits returns whether or not all of the content fits in the viewport
+// without scrolling. When SoftWrap is enabled, wrapped lines are taken into
+// account.
+func (m Model) ContentFits() bool {
+	return len(m.displayLines()) <= m.scrollHeight()
+}
+
+// SetKeyHandlingEnabled enables or disables handling of key presses in Update,
+// which is enabled by default. With it disabled, the viewport can still be
+// scrolled with methods such as LineDown, which is useful when it's part of a
+// larger component that handles keys itself.
+func (m *Model) SetKeyHandlingEnabled(v bool) {
+	m.keysDisabled = !v
+}
+
+// KeyHandlingEnabled returns whether or not key presses are handled in Update.
+func (m Model) KeyHandlingEnabled() bool {
+	return !m.keysDisabled
+}
+
 // SetContent set the pager's text content. For high performance rendering the
 // Sync command should also be called.
+//
+// The content is split into lines and wrapped lazily, on the next Update or
+// the first time it's rendered or measured, so it's cheap to call SetContent
+// many times between frames; only the latest content is processed, once.
+// That's not the case when the viewport is anchored to the bottom, as the
+// content has to be measured to stay at the bottom.
 func (m *Model) SetContent(s string) {
-	s = strings.ReplaceAll(s, "\r\n", "\n") // normalize line endings
-	m.lines = strings.Split(s, "\n")
+	follow := m.following()
+	m.pending = s
+	m.hasPending = true
+	m.contentChanged()
+	if follow {
+		m.GotoBottom()
+	}
+}
 
-	if m.YOffset > len(m.lines)-1 {
+// AppendLines adds lines to the end of the content. If the viewport is
+// anchored to the bottom, it scrolls to show them.
+func (m *Model) AppendLines(lines ...string) {
+	follow := m.following()
+	m.flushContent()
+	for _, line := range lines {
+		m.lines = append(m.lines, splitContent(line)...)
+	}
+	m.folded = m.applyFolds(m.lines)
+	m.contentChanged()
+	m.updateWrap()
+	if follow {
 		m.GotoBottom()
 	}
 }
 
+// following returns whether the viewport should stay at the bottom when the
+// content changes.
+func (m Model) following() bool {
+	return m.BottomAnchored && m.AtBottom()
+}
+
+// splitContent splits content into lines, normalizing line endings.
+func splitContent(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n") // normalize line endings
+	return strings.Split(s, "\n")
+}
+
+// contentLines returns the content lines with collapsed folds replaced by
+// their summaries, splitting any pending content on the fly without storing
+// the result.
+func (m Model) contentLines() []string {
+	if m.hasPending {
+		return m.applyFolds(splitContent(m.pending))
+	}
+	if len(m.folds) > 0 {
+		return m.folded
+	}
+	return m.lines
+}
+
+// rawLines returns the content lines, without folding.
+func (m Model) rawLines() []string {
+	if m.hasPending {
+		return splitContent(m.pending)
+	}
+	return m.lines
+}
+
+// flushContent processes content pending from SetContent, if any.
+func (m *Model) flushContent() {
+	if !m.hasPending {
+		return
+	}
+	m.lines = splitContent(m.pending)
+	m.folded = m.applyFolds(m.lines)
+	m.pending = ""
+	m.hasPending = false
+	m.updateWrap()
+}
+
+// wrapSettings are the settings that affect how the content is soft wrapped.
+type wrapSettings struct {
+	width    int
+	tabWidth int
+	mode     WrapMode
+}
+
+func (m Model) currentWrapSettings() wrapSettings {
+	return wrapSettings{
+		width:    m.contentWidth(),
+		tabWidth: m.TabWidth,
+		mode:     m.WrapMode,
+	}
+}
+
+// Versions of the content, unique across models so that copies of a model
+// whose contents have diverged never share cached lines.
+var contentVersion uint64
+
+// contentChanged records that the content lines have changed, so that the
+// displayed lines are recomputed.
+func (m *Model) contentChanged() {
+	m.version = atomic.AddUint64(&contentVersion, 1)
+}
+
+// displayCache holds the displayed lines for a version of the content, along
+// with the settings they were computed with. It's shared by copies of a
+// model, so that the lines are computed once, whichever of Update or the
+// value-receiver methods such as View and TotalLineCount needs them first,
+// rather than on every call.
+type displayCache struct {
+	version  uint64
+	softWrap bool
+	settings wrapSettings
+	lines    []string
+}
+
+// displayLines returns the lines as they're displayed: the content lines, or
+// the soft wrapped lines if SoftWrap is enabled.
+func (m Model) displayLines() []string {
+	ws := m.currentWrapSettings()
+	c := m.display
+	if c != nil && c.version == m.version && c.softWrap == m.SoftWrap && (!m.SoftWrap || c.settings == ws) {
+		return c.lines
+	}
+
+	lines := m.contentLines()
+	if m.SoftWrap {
+		lines = wrapLines(lines, ws)
+	}
+	if c != nil {
+		*c = displayCache{
+			version:  m.version,
+			softWrap: m.SoftWrap,
+			settings: ws,
+			lines:    lines,
+		}
+	}
+	return lines
+}
+
+// updateWrap processes pending content and rewraps it if soft wrapping is
+// enabled and the content or the settings it was wrapped with have changed,
+// such as after a resize. If that leaves the viewport past the end of the
+// content, it's moved to the bottom.
+func (m *Model) updateWrap() {
+	m.flushContent()
+	if m.YOffset > len(m.displayLines())-1 {
+		m.YOffset = m.maxYOffset()
+	}
+}
+
+// SetStickyTop reserves the given number of lines at the top of the viewport
+// for content that doesn't scroll, such as a summary or column headers. The
+// content is truncated or padded to fit. The content scrolls in the remaining
+// height. Pass 0 to remove the region.
+func (m *Model) SetStickyTop(lines int, content string) {
+	m.stickyTop = stickyLines(lines, content)
+	m.SetYOffset(m.YOffset)
+}
+
+// SetStickyBottom reserves the given number of lines at the bottom of the
+// viewport for content that doesn't scroll, such as a status line. The content
+// is truncated or padded to fit. The content scrolls in the remaining height.
+// Pass 0 to remove the region.
+func (m *Model) SetStickyBottom(lines int, content string) {
+	m.stickyBottom = stickyLines(lines, content)
+	m.SetYOffset(m.YOffset)
+}
+
+// stickyLines splits the content of a sticky region into exactly n lines.
+func stickyLines(n int, content string) []string {
+	if n <= 0 {
+		return nil
+	}
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lines := strings.Split(content, "\n")
+	if len(lines) > n {
+		return lines[:n]
+	}
+	return append(lines, make([]string, n-len(lines))...)
+}
+
+// scrollHeight returns the height of the scrolling portion of the viewport,
+// which is its height less any sticky regions.
+func (m Model) scrollHeight() int {
+	return max(0, m.Height-len(m.stickyTop)-len(m.stickyBottom))
+}
+
 // maxYOffset returns the maximum possible value of the y-offset based on the
 // viewport's content and set height.
 func (m Model) maxYOffset() int {
-	return max(0, len(m.lines)-m.Height)
+	return max(0, len(m.displayLines())-m.scrollHeight())
 }
 
 // visibleLines returns the lines that should currently be visible in the
 // viewport.
 func (m Model) visibleLines() (lines []string) {
-	if len(m.lines) > 0 {
-		top := max(0, m.YOffset)
-		bottom := clamp(m.YOffset+m.Height, top, len(m.lines))
-		lines = m.lines[top:bottom]
+	all := m.displayLines()
+	if len(all) > 0 {
+		// YOffset can be set directly, so keep it in bounds here as well.
+		top := clamp(m.YOffset, 0, max(0, len(all)-m.scrollHeight()))
+		bottom := min(top+m.scrollHeight(), len(all))
+		lines = m.renderLines(all[top:bottom], top)
 	}
 	return lines
 }
 
+// RenderLines renders the lines of the content from start up to but not
+// including end, regardless of the scroll position, such as for a minimap or
+// a preview. They're rendered as they are in View, so when SoftWrap is
+// enabled, the lines are the wrapped lines. The range is kept within the
+// bounds of the content.
+func (m Model) RenderLines(start, end int) string {
+	all := m.displayLines()
+	start = clamp(start, 0, len(all))
+	end = clamp(end, start, len(all))
+	return strings.Join(m.renderLines(all[start:end], start), "\n")
+}
+
+// renderLines renders the given lines, the first of which is the line at
+// index top of the displayed lines. Only the given lines are processed so
+// that the cost of rendering doesn't depend on the size of the content or the
+// total number of search matches.
+func (m Model) renderLines(lines []string, top int) []string {
+	guides := m.visibleColumnGuides()
+	if m.searchTerm == "" && m.TabWidth <= 0 && len(guides) == 0 && len(m.highlights) == 0 && m.leftMargin == 0 {
+		return lines
+	}
+
+	rendered := make([]string, len(lines))
+	for i, line := range lines {
+		if m.searchTerm != "" {
+			line = m.highlightMatches(line, top+i)
+		}
+		if m.TabWidth > 0 {
+			line = expandTabs(line, m.TabWidth)
+		}
+		if len(guides) > 0 {
+			line = drawColumnGuides(line, guides, m.ColumnGuideStyle)
+		}
+		if m.isHighlighted(top + i) {
+			line = m.HighlightStyle.Render(line)
+		}
+		rendered[i] = m.marginView() + line
+	}
+	return rendered
+}
+
+// SetLeftMargin indents the content by the given number of columns, such as to
+// display it as a quote. The margin is styled with LeftMarginStyle and reduces
+// the width available to the content, which is taken into account when soft
+// wrapping. Negative values are treated as 0.
+func (m *Model) SetLeftMargin(n int) {
+	m.leftMargin = max(0, n)
+	m.updateWrap()
+}
+
+// LeftMargin returns the number of columns the content is indented by.
+func (m Model) LeftMargin() int {
+	return m.leftMargin
+}
+
+// contentWidth returns the number of columns available to the content.
+func (m Model) contentWidth() int {
+	return m.Width - m.Style.GetHorizontalFrameSize() - m.leftMargin
+}
+
+// marginView renders the left margin, if any.
+func (m Model) marginView() string {
+	if m.leftMargin == 0 {
+		return ""
+	}
+	return m.LeftMarginStyle.Render(strings.Repeat(" ", m.leftMargin))
+}
+
+// SetColumnGuides sets the columns at which to draw vertical guides, such as
+// to mark the 80th column of code. Columns are 1-based, so a guide at 80 is
+// drawn on the 80th column. Guides are drawn by styling the cells at those
+// columns with ColumnGuideStyle, so they don't take up any width, and guides
+// beyond the width of the viewport aren't drawn. Duplicate columns are drawn
+// once. Pass nil to remove them.
+func (m *Model) SetColumnGuides(columns []int) {
+	m.columnGuides = nil
+	for _, c := range columns {
+		if c > 0 {
+			m.columnGuides = append(m.columnGuides, c-1)
+		}
+	}
+	sort.Ints(m.columnGuides)
+
+	// Remove duplicates.
+	n := 0
+	for i, c := range m.columnGuides {
+		if i == 0 || c != m.columnGuides[n-1] {
+			m.columnGuides[n] = c
+			n++
+		}
+	}
+	m.columnGuides = m.columnGuides[:n]
+}
+
+// visibleColumnGuides returns the column guides that fit in the viewport.
+func (m Model) visibleColumnGuides() []int {
+	width := m.contentWidth()
+	for i, c := range m.columnGuides {
+		if c >= width {
+			return m.columnGuides[:i]
+		}
+	}
+	return m.columnGuides
+}
+
+// SetHighlightedLines sets the lines to style with HighlightStyle, such as lines
+// with errors. Lines are zero-based, like YOffset; when SoftWrap is enabled,
+// they refer to the wrapped lines. Use NextHighlight and PrevHighlight to
+// scroll to them. Pass nil to remove the highlights.
+func (m *Model) SetHighlightedLines(lines []int) {
+	m.highlights = nil
+	for _, l := range lines {
+		if l >= 0 {
+			m.highlights = append(m.highlights, l)
+		}
+	}
+	sort.Ints(m.highlights)
+	m.currentHighlight = -1
+}
+
+// HighlightedLines returns the highlighted lines in ascending order.
+func (m Model) HighlightedLines() []int {
+	return m.highlights
+}
+
+// isHighlighted returns whether or not the given line is highlighted.
+func (m Model) isHighlighted(line int) bool {
+	i := sort.SearchInts(m.highlights, line)
+	return i < len(m.highlights) && m.highlights[i] == line
+}
+
+// highlightsBefore returns the highlighted lines before line n, which is
+// usually the number of lines in the content.
+func (m Model) highlightsBefore(n int) []int {
+	return m.highlights[:sort.SearchInts(m.highlights, n)]
+}
+
+// NextHighlight scrolls to the next highlighted line after the current one,
+// wrapping around to the top of the content if necessary. It returns whether
+// a highlighted line was found.
+func (m *Model) NextHighlight() bool {
+	n := len(m.displayLines())
+	from := m.currentHighlight
+	if from < 0 {
+		from = max(0, m.YOffset) - 1
+	}
+
+	found := m.highlightsBefore(n)
+	if len(found) == 0 {
+		return false
+	}
+
+	line := found[0]
+	if i := sort.SearchInts(found, from+1); i < len(found) {
+		line = found[i]
+	}
+	m.gotoLine(line)
+	m.currentHighlight = line
+	return true
+}
+
+// PrevHighlight scrolls to the highlighted line before the current one,
+// wrapping around to the bottom of the content if necessary. It returns
+// whether a highlighted line was found.
+func (m *Model) PrevHighlight() bool {
+	n := len(m.displayLines())
+	from := m.currentHighlight
+	if from < 0 {
+		from = min(n, max(0, m.YOffset)+m.scrollHeight())
+	}
+
+	found := m.highlightsBefore(n)
+	if len(found) == 0 {
+		return false
+	}
+
+	line := found[len(found)-1]
+	if i := sort.SearchInts(found, from); i > 0 {
+		line = found[i-1]
+	}
+	m.gotoLine(line)
+	m.currentHighlight = line
+	return true
+}
+
+// fold is a region of the content that can be collapsed to a summary line.
+type fold struct {
+	start, end int
+	summary    string
+	collapsed  bool
+}
+
+// AddFold adds a collapsed fold over the content lines from start to end,
+// inclusive, which is rendered as the given summary line, styled with
+// FoldStyle. Lines are zero-based and refer to the content as passed to
+// SetContent, before soft wrapping. Folds can't overlap, so any folds
+// overlapping the new one are removed. Folds are kept when the content
+// changes; use ClearFolds to remove them.
+func (m *Model) AddFold(start, end int, summary string) {
+	if start < 0 || end < start {
+		return
+	}
+
+	folds := make([]fold, 0, len(m.folds)+1)
+	for _, f := range m.folds {
+		if f.end < start || f.start > end {
+			folds = append(folds, f)
+		}
+	}
+	i := sort.Search(len(folds), func(i int) bool { return folds[i].start > start })
+	folds = append(folds, fold{})
+	copy(folds[i+1:], folds[i:])
+	folds[i] = fold{start: start, end: end, summary: summary, collapsed: true}
+
+	m.folds = folds
+	m.refold()
+}
+
+// ClearFolds removes all folds, expanding any collapsed ones.
+func (m *Model) ClearFolds() {
+	m.folds = nil
+	m.refold()
+}
+
+// ToggleFold collapses or expands the first fold that starts in view. It
+// returns whether there was a fold to toggle.
+func (m *Model) ToggleFold() bool {
+	i := m.visibleFold()
+	if i < 0 {
+		return false
+	}
+	// Copy the folds so that copies of the model aren't affected.
+	folds := make([]fold, len(m.folds))
+	copy(folds, m.folds)
+	folds[i].collapsed = !folds[i].collapsed
+	m.folds = folds
+	m.refold()
+	return true
+}
+
+// refold reapplies the folds to the content after they've changed.
+func (m *Model) refold() {
+	m.flushContent()
+	m.folded = m.applyFolds(m.lines)
+	m.contentChanged()
+	m.SetYOffset(m.YOffset)
+}
+
+// applyFolds returns the given lines with collapsed folds replaced by their
+// summaries.
+func (m Model) applyFolds(lines []string) []string {
+	if len(m.folds) == 0 {
+		return lines
+	}
+
+	folded := make([]string, 0, len(lines))
+	next := 0
+	for _, f := range m.folds {
+		if !f.collapsed || f.start >= len(lines) {
+			continue
+		}
+		folded = append(folded, lines[next:f.start]...)
+		folded = append(folded, m.FoldStyle.Render(f.summary))
+		next = min(f.end+1, len(lines))
+	}
+	return append(folded, lines[next:]...)
+}
+
+// visibleFold returns the index of the first fold that starts in view, or -1
+// if there's none.
+func (m Model) visibleFold() int {
+	ws := m.currentWrapSettings()
+	height := func(line string) int {
+		if !m.SoftWrap {
+			return 1
+		}
+		return len(wrapLines([]string{line}, ws))
+	}
+
+	lines := m.rawLines()
+	top := max(0, m.YOffset)
+	bottom := top + m.scrollHeight()
+
+	row, i := 0, 0
+	for l := 0; l < len(lines) && row < bottom; {
+		for i < len(m.folds) && m.folds[i].end < l {
+			i++
+		}
+		if i < len(m.folds) && m.folds[i].start == l {
+			if row >= top {
+				return i
+			}
+			if f := m.folds[i]; f.collapsed {
+				row += height(m.FoldStyle.Render(f.summary))
+				l = f.end + 1
+				continue
+			}
+		}
+		row += height(lines[l])
+		l++
+	}
+	return -1
+}
+
+// Search sets the term to search the content for. Matches are highlighted with
+// SearchMatchStyle as they come into view; use NextMatch and PrevMatch to
+// scroll to them. Matching is case-sensitive and performed against the text of
+// the content, line by line, ignoring any styling, so styled content can be
+// searched; when SoftWrap is enabled, that's the wrapped lines. An empty term
+// clears the search.
+func (m *Model) Search(term string) {
+	m.searchTerm = term
+	m.matchLine = -1
+	m.matchCol = 0
+}
+
+// SearchTerm returns the current search term.
+func (m Model) SearchTerm() string {
+	return m.searchTerm
+}
+
+// ClearSearch clears the current search term and its highlighting.
+func (m *Model) ClearSearch() {
+	m.Search("")
+}
+
+// NextMatch scrolls to the next match of the search term after the current
+// match, wrapping around to the top of the content if necessary. It returns
+// whether a match was found.
+func (m *Model) NextMatch() bool {
+	lines := m.displayLines()
+	if m.searchTerm == "" || len(lines) == 0 {
+		return false
+	}
+
+	line, from := m.matchLine, m.matchCol+1
+	if line < 0 {
+		line, from = max(0, m.YOffset), 0
+	}
+
+	for i := 0; i <= len(lines); i++ {
+		l := (line + i) % len(lines)
+		s := stripANSI(lines[l])
+		if i > 0 {
+			from = 0
+		}
+		if from > len(s) {
+			continue
+		}
+		if col := strings.Index(s[from:], m.searchTerm); col >= 0 {
+			m.gotoMatch(l, from+col)
+			return true
+		}
+	}
+	return false
+}
+
+// PrevMatch scrolls to the match of the search term before the current match,
+// wrapping around to the bottom of the content if necessary. It returns
+// whether a match was found.
+func (m *Model) PrevMatch() bool {
+	lines := m.displayLines()
+	if m.searchTerm == "" || len(lines) == 0 {
+		return false
+	}
+
+	line, to := m.matchLine, m.matchCol
+	if line < 0 {
+		line = min(len(lines)-1, max(0, m.YOffset))
+		to = len(stripANSI(lines[line]))
+	}
+
+	for i := 0; i <= len(lines); i++ {
+		l := (line - i + len(lines)) % len(lines)
+		s := stripANSI(lines[l])
+		if i > 0 {
+			to = len(s)
+		}
+		if col := strings.LastIndex(s[:min(to, len(s))], m.searchTerm); col >= 0 {
+			m.gotoMatch(l, col)
+			return true
+		}
+	}
+	return false
+}
+
+// gotoMatch sets the current match and, if it's not visible, scrolls so that
+// it's centered in the viewport.
+func (m *Model) gotoMatch(line, col int) {
+	m.matchLine, m.matchCol = line, col
+	m.gotoLine(line)
+}
+
+// gotoLine scrolls so that the given line is centered in the viewport, if it's
+// not already visible.
+func (m *Model) gotoLine(line int) {
+	if h := m.scrollHeight(); line < m.YOffset || line >= m.YOffset+h {
+		m.SetYOffset(line - h/2)
+	}
+}
+
+// highlightMatches styles the matches of the search term within the given line.
+// Matches are found in the text of the line, so they can span escape
+// sequences; those within a match are applied after it, and the styling in
+// effect is restored after each match.
+func (m Model) highlightMatches(line string, lineNum int) string {
+	text := stripANSI(line)
+	if !strings.Contains(text, m.searchTerm) {
+		return line
+	}
+
+	var (
+		b     strings.Builder
+		seq   strings.Builder // escape sequence being read
+		sgr   string          // styling in effect at the current position
+		pos   int             // byte offset in the text
+		end   = -1            // end of the match being written, if any
+		inSeq bool
+	)
+	next := strings.Index(text, m.searchTerm)
+	for _, r := range line {
+		switch {
+		case r == ansi.Marker:
+			inSeq = true
+			seq.Reset()
+			seq.WriteRune(r)
+		case inSeq:
+			seq.WriteRune(r)
+			if ansi.IsTerminator(r) {
+				inSeq = false
+				sgr = activeStyles(sgr, seq.String())
+				if end < 0 {
+					b.WriteString(seq.String())
+				}
+			}
+		default:
+			if pos == next {
+				style := m.SearchMatchStyle
+				if lineNum == m.matchLine && pos == m.matchCol {
+					style = m.CurrentMatchStyle
+				}
+				b.WriteString(style.Render(m.searchTerm))
+				end = pos + len(m.searchTerm)
+			}
+			if end < 0 {
+				b.WriteRune(r)
+			}
+			pos += utf8.RuneLen(r)
+			if pos == end {
+				b.WriteString(sgr)
+				end = -1
+				if i := strings.Index(text[pos:], m.searchTerm); i >= 0 {
+					next = pos + i
+				}
+			}
+		}
+	}
+	if inSeq {
+		b.WriteString(seq.String())
+	}
+	return b.String()
+}
+
+// stripANSI returns the given string without escape sequences.
+func stripANSI(s string) string {
+	if !strings.ContainsRune(s, ansi.Marker) {
+		return s
+	}
+	var (
+		b     strings.Builder
+		inSeq bool
+	)
+	for _, r := range s {
+		switch {
+		case r == ansi.Marker:
+			inSeq = true
+		case inSeq:
+			if ansi.IsTerminator(r) {
+				inSeq = false
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 // scrollArea returns the scrollable boundaries for high performance rendering.
 func (m Model) scrollArea() (top, bottom int) {
-	top = max(0, m.YPosition)
-	bottom = max(top, top+m.Height)
+	top = max(0, m.YPosition) + len(m.stickyTop)
+	bottom = max(top, top+m.scrollHeight())
 	if top > 0 && bottom > top {
 		bottom--
 	}
 	return top, bottom
 }
 
-// SetYOffset sets the Y offset.
+// ScrollTo scrolls to the given fraction of the content, between 0 (the top)
+// and 1 (the bottom).
+func (m *Model) ScrollTo(fraction float64) {
+	m.updateWrap()
+	m.SetYOffset(int(math.Round(fraction * float64(m.maxYOffset()))))
+}
+
+// SetYOffset sets the Y offset, keeping it within the bounds of the content.
 func (m *Model) SetYOffset(n int) {
+	m.updateWrap()
 	m.YOffset = clamp(n, 0, m.maxYOffset())
 }
 
@@ -147,7 +985,7 @@ func (m *Model) ViewDown() []string {
 		return nil
 	}
 
-	m.SetYOffset(m.YOffset + m.Height)
+	m.SetYOffset(m.YOffset + m.scrollHeight())
 	return m.visibleLines()
 }
 
@@ -157,7 +995,7 @@ func (m *Model) ViewUp() []string {
 		return nil
 	}
 
-	m.SetYOffset(m.YOffset - m.Height)
+	m.SetYOffset(m.YOffset - m.scrollHeight())
 	return m.visibleLines()
 }
 
@@ -167,7 +1005,7 @@ func (m *Model) HalfViewDown() (lines []string) {
 		return nil
 	}
 
-	m.SetYOffset(m.YOffset + m.Height/2)
+	m.SetYOffset(m.YOffset + m.scrollHeight()/2)
 	return m.visibleLines()
 }
 
@@ -177,7 +1015,7 @@ func (m *Model) HalfViewUp() (lines []string) {
 		return nil
 	}
 
-	m.SetYOffset(m.YOffset - m.Height/2)
+	m.SetYOffset(m.YOffset - m.scrollHeight()/2)
 	return m.visibleLines()
 }
 
@@ -223,13 +1061,19 @@ func (m *Model) GotoBottom() (lines []string) {
 	return m.visibleLines()
 }
 
+// ScrollToBottom scrolls to the bottom of the content. When BottomAnchored is
+// set, this is how the viewport is anchored again after being scrolled up.
+func (m *Model) ScrollToBottom() {
+	m.GotoBottom()
+}
+
 // Sync tells the renderer where the viewport will be located and requests
 // a render of the current state of the viewport. It should be called for the
 // first render and after a window resize.
 //
 // For high performance rendering only.
 func Sync(m Model) tea.Cmd {
-	if len(m.lines) == 0 {
+	if len(m.displayLines()) == 0 {
 		return nil
 	}
 	top, bottom := m.scrollArea()
@@ -275,47 +1119,76 @@ func (m Model) updateAsModel(msg tea.Msg) (Model, tea.Cmd) {
 	if !m.initialized {
 		m.setInitialValues()
 	}
+	m.updateWrap()
 
-	var cmd tea.Cmd
+	var (
+		cmd                      tea.Cmd
+		atTop, atBottom          = m.AtTop(), m.AtBottom()
+		scrolledUp, scrolledDown bool
+	)
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.keysDisabled {
+			break
+		}
 		switch {
 		case key.Matches(msg, m.KeyMap.PageDown):
+			scrolledDown = true
 			lines := m.ViewDown()
 			if m.HighPerformanceRendering {
 				cmd = ViewDown(m, lines)
 			}
 
 		case key.Matches(msg, m.KeyMap.PageUp):
+			scrolledUp = true
 			lines := m.ViewUp()
 			if m.HighPerformanceRendering {
 				cmd = ViewUp(m, lines)
 			}
 
 		case key.Matches(msg, m.KeyMap.HalfPageDown):
+			scrolledDown = true
 			lines := m.HalfViewDown()
 			if m.HighPerformanceRendering {
 				cmd = ViewDown(m, lines)
 			}
 
 		case key.Matches(msg, m.KeyMap.HalfPageUp):
+			scrolledUp = true
 			lines := m.HalfViewUp()
 			if m.HighPerformanceRendering {
 				cmd = ViewUp(m, lines)
 			}
 
 		case key.Matches(msg, m.KeyMap.Down):
+			scrolledDown = true
 			lines := m.LineDown(1)
 			if m.HighPerformanceRendering {
 				cmd = ViewDown(m, lines)
 			}
 
 		case key.Matches(msg, m.KeyMap.Up):
+			scrolledUp = true
 			lines := m.LineUp(1)
 			if m.HighPerformanceRendering {
 				cmd = ViewUp(m, lines)
 			}
+
+		case key.Matches(msg, m.KeyMap.NextHighlight):
+			if m.NextHighlight() && m.HighPerformanceRendering {
+				cmd = Sync(m)
+			}
+
+		case key.Matches(msg, m.KeyMap.PrevHighlight):
+			if m.PrevHighlight() && m.HighPerformanceRendering {
+				cmd = Sync(m)
+			}
+
+		case key.Matches(msg, m.KeyMap.ToggleFold):
+			if m.ToggleFold() && m.HighPerformanceRendering {
+				cmd = Sync(m)
+			}
 		}
 
 	case tea.MouseMsg:
@@ -324,12 +1197,14 @@ func (m Model) updateAsModel(msg tea.Msg) (Model, tea.Cmd) {
 		}
 		switch msg.Type {
 		case tea.MouseWheelUp:
+			scrolledUp = true
 			lines := m.LineUp(m.MouseWheelDelta)
 			if m.HighPerformanceRendering {
 				cmd = ViewUp(m, lines)
 			}
 
 		case tea.MouseWheelDown:
+			scrolledDown = true
 			lines := m.LineDown(m.MouseWheelDelta)
 			if m.HighPerformanceRendering {
 				cmd = ViewDown(m, lines)
@@ -337,31 +1212,238 @@ func (m Model) updateAsModel(msg tea.Msg) (Model, tea.Cmd) {
 		}
 	}
 
+	if m.AtTop() && (!atTop || scrolledUp) {
+		cmd = tea.Batch(cmd, reachedTop)
+	}
+	if m.AtBottom() && (!atBottom || scrolledDown) {
+		cmd = tea.Batch(cmd, reachedBottom)
+	}
+
 	return m, cmd
 }
 
+func reachedTop() tea.Msg {
+	return ReachedTopMsg{}
+}
+
+func reachedBottom() tea.Msg {
+	return ReachedBottomMsg{}
+}
+
 // View renders the viewport into a string.
 func (m Model) View() string {
 	if m.HighPerformanceRendering {
 		// Just send newlines since we're going to be rendering the actual
 		// content seprately. We still need to send something that equals the
 		// height of this view so that the Bubble Tea standard renderer can
-		// position anything below this view properly.
-		return strings.Repeat("\n", m.Height-1)
+		// position anything below this view properly. Sticky regions aren't
+		// part of the scroll area, so they're rendered as usual.
+		lines := append([]string{}, m.stickyTop...)
+		lines = append(lines, make([]string, m.scrollHeight())...)
+		lines = append(lines, m.stickyBottom...)
+		return strings.Join(lines, "\n")
 	}
 
 	lines := m.visibleLines()
 
-	// Fill empty space with newlines
+	// Fill empty space with newlines, or the fill character if there is one
 	extraLines := ""
-	if len(lines) < m.Height {
-		extraLines = strings.Repeat("\n", max(0, m.Height-len(lines)))
+	if h := m.scrollHeight(); len(lines) < h {
+		if m.FillCharacter != 0 {
+			fill := m.marginView() + m.FillStyle.Render(string(m.FillCharacter))
+			lines = append([]string{}, lines...) // don't modify the content
+			for len(lines) < h {
+				lines = append(lines, fill)
+			}
+		} else {
+			extraLines = strings.Repeat("\n", max(0, h-len(lines)))
+		}
+	}
+
+	content := strings.Join(lines, "\n") + extraLines
+	if len(m.stickyTop) > 0 {
+		content = strings.Join(m.stickyTop, "\n") + "\n" + content
+	}
+	if len(m.stickyBottom) > 0 {
+		content += "\n" + strings.Join(m.stickyBottom, "\n")
 	}
 
 	return m.Style.Copy().
 		UnsetWidth().
 		UnsetHeight().
-		Render(strings.Join(lines, "\n") + extraLines)
+		Render(content)
+}
+
+// expandTabs replaces tabs in the given string with spaces up to the next tab
+// stop. ANSI escape sequences are passed through and don't count towards the
+// column.
+func expandTabs(s string, tabWidth int) string {
+	if !strings.ContainsRune(s, '\t') {
+		return s
+	}
+
+	var (
+		b     strings.Builder
+		col   int
+		inSeq bool
+	)
+	for _, r := range s {
+		switch {
+		case r == ansi.Marker:
+			inSeq = true
+			b.WriteRune(r)
+		case inSeq:
+			if ansi.IsTerminator(r) {
+				inSeq = false
+			}
+			b.WriteRune(r)
+		case r == '\t':
+			n := tabWidth - col%tabWidth
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+		default:
+			b.WriteRune(r)
+			col += runewidth.RuneWidth(r)
+		}
+	}
+	return b.String()
+}
+
+// wrapLines soft wraps the given lines to the width in the given settings.
+// Tabs are expanded beforehand, if enabled, so they're accounted for.
+func wrapLines(lines []string, ws wrapSettings) []string {
+	if ws.width <= 0 {
+		return lines
+	}
+
+	wrapped := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if ws.tabWidth > 0 {
+			line = expandTabs(line, ws.tabWidth)
+		}
+		if ansi.PrintableRuneWidth(line) <= ws.width {
+			wrapped = append(wrapped, line)
+			continue
+		}
+
+		switch ws.mode {
+		case CharWrap:
+			w := wrap.NewWriter(ws.width)
+			w.PreserveSpace = true
+			_, _ = w.Write([]byte(line))
+			line = w.String()
+		default:
+			// Break at word boundaries, then break any words that are still
+			// too long to fit.
+			line = wrap.String(wordwrap.String(line, ws.width), ws.width)
+		}
+		wrapped = append(wrapped, reopenStyles(strings.Split(line, "\n"))...)
+	}
+	return wrapped
+}
+
+// reopenStyles makes each of the given lines, which were wrapped from a single
+// line, self-contained in terms of styling: styles still in effect at the end
+// of a line are reset there and re-applied at the start of the next line.
+// This keeps styles from bleeding into whatever's rendered alongside a line,
+// such as borders, and from being lost when lines are rendered separately.
+func reopenStyles(lines []string) []string {
+	var sgr string
+	for i, line := range lines {
+		prefix := sgr
+		sgr = activeStyles(sgr, line)
+		if prefix != "" {
+			line = prefix + line
+		}
+		if sgr != "" && i < len(lines)-1 {
+			line += "\x1b[0m"
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+// activeStyles returns the SGR sequences in effect after the given string,
+// given those in effect before it.
+func activeStyles(sgr, s string) string {
+	var (
+		seq   strings.Builder
+		inSeq bool
+	)
+	for _, r := range s {
+		switch {
+		case r == ansi.Marker:
+			inSeq = true
+			seq.Reset()
+			seq.WriteRune(r)
+		case inSeq:
+			seq.WriteRune(r)
+			if ansi.IsTerminator(r) {
+				inSeq = false
+				if r == 'm' {
+					if s := seq.String(); s == "\x1b[0m" || s == "\x1b[m" {
+						sgr = ""
+					} else {
+						sgr += s
+					}
+				}
+			}
+		}
+	}
+	return sgr
+}
+
+// drawColumnGuides styles the cells at the given zero-based columns, which
+// must be in ascending order, padding the line with spaces if it's too short to
+// reach them. Styling in the line that's interrupted by a guide is restored
+// after it.
+func drawColumnGuides(line string, guides []int, style lipgloss.Style) string {
+	var (
+		b     strings.Builder
+		seq   strings.Builder // escape sequence being read
+		sgr   string          // styling in effect at the current position
+		col   int
+		next  int // next guide to draw
+		inSeq bool
+	)
+	for _, r := range line {
+		switch {
+		case r == ansi.Marker:
+			inSeq = true
+			seq.Reset()
+			seq.WriteRune(r)
+		case inSeq:
+			seq.WriteRune(r)
+			if ansi.IsTerminator(r) {
+				inSeq = false
+				b.WriteString(seq.String())
+				sgr = activeStyles(sgr, seq.String())
+			}
+		default:
+			w := runewidth.RuneWidth(r)
+			if next < len(guides) && guides[next] < col+w {
+				b.WriteString(style.Render(string(r)))
+				b.WriteString(sgr)
+				for next < len(guides) && guides[next] < col+w {
+					next++
+				}
+			} else {
+				b.WriteRune(r)
+			}
+			col += w
+		}
+	}
+	if inSeq {
+		b.WriteString(seq.String())
+	}
+
+	// Pad the line to reach the remaining guides.
+	for ; next < len(guides); next++ {
+		b.WriteString(strings.Repeat(" ", max(0, guides[next]-col)))
+		b.WriteString(style.Render(" "))
+		col = guides[next] + 1
+	}
+	return b.String()
 }
 
 func clamp(v, low, high int) int {
@@ -0,0 +1,438 @@
+package viewport
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func newTestModel(lines, height int) Model {
+	content := make([]string, lines)
+	for i := range content {
+		content[i] = strconv.Itoa(i)
+	}
+	m := New(10, height)
+	m.SetContent(strings.Join(content, "\n"))
+	return m
+}
+
+func TestSetYOffsetClamps(t *testing.T) {
+	m := newTestModel(20, 5)
+
+	for _, tc := range []struct {
+		offset, expected int
+	}{
+		{-5, 0},
+		{0, 0},
+		{10, 10},
+		{15, 15},
+		{100, 15},
+	} {
+		m.SetYOffset(tc.offset)
+		if m.YOffset != tc.expected {
+			t.Errorf("SetYOffset(%d): expected offset %d, got %d", tc.offset, tc.expected, m.YOffset)
+		}
+	}
+}
+
+func TestOutOfRangeYOffset(t *testing.T) {
+	m := newTestModel(20, 5)
+
+	m.YOffset = 100
+	if lines := m.visibleLines(); len(lines) != 5 || lines[4] != "19" {
+		t.Errorf("expected the last lines to be visible, got %v", lines)
+	}
+
+	m.YOffset = -10
+	if lines := m.visibleLines(); len(lines) != 5 || lines[0] != "0" {
+		t.Errorf("expected the first lines to be visible, got %v", lines)
+	}
+}
+
+func TestScrollTo(t *testing.T) {
+	m := newTestModel(21, 1)
+
+	for _, tc := range []struct {
+		fraction float64
+		expected int
+	}{
+		{0, 0},
+		{0.5, 10},
+		{1, 20},
+		{-1, 0},
+		{2, 20},
+	} {
+		m.ScrollTo(tc.fraction)
+		if m.YOffset != tc.expected {
+			t.Errorf("ScrollTo(%v): expected offset %d, got %d", tc.fraction, tc.expected, m.YOffset)
+		}
+	}
+}
+
+func TestSoftWrapReopensStyles(t *testing.T) {
+	m := New(5, 5)
+	m.SoftWrap = true
+	m.SetContent("\x1b[31mhello world\x1b[0m")
+
+	expected := []string{
+		"\x1b[31mhello\x1b[0m",
+		"\x1b[31mworld\x1b[0m",
+	}
+	lines := m.visibleLines()
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d: %q", len(expected), len(lines), lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("line %d: expected %q, got %q", i, expected[i], lines[i])
+		}
+	}
+}
+
+func TestSetContentUsesLatestContent(t *testing.T) {
+	m := newTestModel(20, 5)
+	m.GotoBottom()
+
+	m.SetContent("a\nb\nc")
+	m.SetContent("a\nb")
+	if n := m.TotalLineCount(); n != 2 {
+		t.Fatalf("expected 2 lines before update, got %d", n)
+	}
+
+	m, _ = m.Update(nil)
+	if n := m.TotalLineCount(); n != 2 {
+		t.Fatalf("expected 2 lines, got %d", n)
+	}
+	if m.YOffset != 0 {
+		t.Fatalf("expected y offset to be clamped to 0, got %d", m.YOffset)
+	}
+}
+
+func TestNextHighlight(t *testing.T) {
+	m := newTestModel(100, 10)
+	m.SetHighlightedLines([]int{50, 5, 200})
+
+	for _, tc := range []struct {
+		next         bool
+		expectedLine int
+	}{
+		{true, 5},
+		{true, 50},
+		{true, 5},
+		{false, 50},
+	} {
+		var ok bool
+		if tc.next {
+			ok = m.NextHighlight()
+		} else {
+			ok = m.PrevHighlight()
+		}
+		if !ok {
+			t.Fatal("expected a highlighted line to be found")
+		}
+		if tc.expectedLine < m.YOffset || tc.expectedLine >= m.YOffset+m.Height {
+			t.Fatalf("expected line %d to be visible, y offset is %d", tc.expectedLine, m.YOffset)
+		}
+	}
+	if m.YOffset != 45 {
+		t.Fatalf("expected line 50 to be centered, got y offset %d", m.YOffset)
+	}
+}
+
+func TestFolds(t *testing.T) {
+	m := newTestModel(20, 5)
+	m.AddFold(2, 9, "folded")
+
+	if n := m.TotalLineCount(); n != 13 {
+		t.Fatalf("expected 13 lines with the fold collapsed, got %d", n)
+	}
+	if !strings.Contains(m.View(), "folded") {
+		t.Fatal("expected the fold summary to be visible")
+	}
+
+	if !m.ToggleFold() {
+		t.Fatal("expected a fold to be toggled")
+	}
+	if n := m.TotalLineCount(); n != 20 {
+		t.Fatalf("expected 20 lines with the fold expanded, got %d", n)
+	}
+
+	m.SetYOffset(5)
+	if m.ToggleFold() {
+		t.Fatal("expected no fold to be in view")
+	}
+}
+
+func TestToggleFoldDoesNotAffectCopies(t *testing.T) {
+	m := newTestModel(20, 5)
+	m.AddFold(2, 9, "folded")
+	snapshot := m
+
+	m.ToggleFold()
+	if !strings.Contains(snapshot.View(), "folded") {
+		t.Fatal("expected the copy's fold to stay collapsed")
+	}
+	if !snapshot.ToggleFold() {
+		t.Fatal("expected a fold to be toggled")
+	}
+	if n := snapshot.TotalLineCount(); n != 20 {
+		t.Fatalf("expected 20 lines with the copy's fold expanded, got %d", n)
+	}
+}
+
+func TestKeyHandlingDisabled(t *testing.T) {
+	m := newTestModel(20, 5)
+	m.SetKeyHandlingEnabled(false)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if m.YOffset != 0 {
+		t.Fatalf("expected keys to be ignored, got y offset %d", m.YOffset)
+	}
+
+	m.LineDown(1)
+	if m.YOffset != 1 {
+		t.Fatalf("expected to scroll programmatically, got y offset %d", m.YOffset)
+	}
+}
+
+func TestLeftMargin(t *testing.T) {
+	m := New(10, 5)
+	m.SoftWrap = true
+	m.SetLeftMargin(2)
+	m.SetContent("aaaa bbbb cccc")
+
+	lines := strings.Split(m.View(), "\n")
+	if lines[0] != "  aaaa" || lines[1] != "  bbbb" {
+		t.Fatalf("expected content to be indented and wrapped to 8 columns, got %q", lines)
+	}
+}
+
+func TestRenderLines(t *testing.T) {
+	m := newTestModel(20, 5)
+	m.SetLeftMargin(1)
+
+	if v := m.RenderLines(10, 12); v != " 10\n 11" {
+		t.Fatalf("expected lines 10 and 11 to be rendered, got %q", v)
+	}
+	if v := m.RenderLines(18, 100); v != " 18\n 19" {
+		t.Fatalf("expected the range to be clamped, got %q", v)
+	}
+}
+
+func TestBottomAnchored(t *testing.T) {
+	m := New(10, 5)
+	m.BottomAnchored = true
+	m.SetContent("0\n1\n2\n3\n4\n5")
+	if m.YOffset != 1 {
+		t.Fatalf("expected content to be anchored to the bottom, got y offset %d", m.YOffset)
+	}
+
+	m.AppendLines("6", "7")
+	if m.YOffset != 3 {
+		t.Fatalf("expected to follow appended lines, got y offset %d", m.YOffset)
+	}
+
+	m.LineUp(2)
+	m.AppendLines("8", "9")
+	if m.YOffset != 1 {
+		t.Fatalf("expected appending while scrolled up to keep the view, got y offset %d", m.YOffset)
+	}
+	if m.TotalLineCount() != 10 {
+		t.Fatalf("expected 10 lines, got %d", m.TotalLineCount())
+	}
+
+	m.ScrollToBottom()
+	m.AppendLines("10")
+	if !m.AtBottom() || m.YOffset != 6 {
+		t.Fatalf("expected to follow again after scrolling to the bottom, got y offset %d", m.YOffset)
+	}
+}
+
+func TestColumnGuides(t *testing.T) {
+	m := New(10, 2)
+	m.ColumnGuideStyle = m.ColumnGuideStyle.Reverse(true)
+	m.SetColumnGuides([]int{4, 2, 4, 20})
+	m.SetContent("abcdef\nab")
+
+	lines := m.RenderLines(0, 2)
+	guide := func(s string) string { return m.ColumnGuideStyle.Render(s) }
+	expected := "a" + guide("b") + "c" + guide("d") + "ef\n" +
+		"a" + guide("b") + " " + guide(" ")
+	if lines != expected {
+		t.Fatalf("expected guides at columns 2 and 4, got %q", lines)
+	}
+	if v := m.View(); !strings.Contains(v, guide("d")) {
+		t.Fatalf("expected guides to be drawn in the view, got %q", v)
+	}
+}
+
+func TestSoftWrap(t *testing.T) {
+	m := New(6, 2)
+	m.SoftWrap = true
+	m.SetContent("aa bbbbbbbb cc")
+
+	lines := m.displayLines()
+	expected := []string{"aa", "bbbbbb", "bb", "cc"}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected word wrapping with long words broken, got %q", lines)
+	}
+	if again := m.displayLines(); &again[0] != &lines[0] {
+		t.Fatal("expected wrapped lines to be cached between reads")
+	}
+
+	m.WrapMode = CharWrap
+	lines = m.displayLines()
+	expected = []string{"aa bbb", "bbbbb ", "cc"}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected character wrapping, got %q", lines)
+	}
+
+	// Widening the viewport leaves fewer lines, so the y offset is clamped.
+	m.WrapMode = WordWrap
+	m.GotoBottom()
+	if m.YOffset != 2 {
+		t.Fatalf("expected y offset 2, got %d", m.YOffset)
+	}
+	m.Width = 20
+	m, _ = m.Update(nil)
+	if m.TotalLineCount() != 1 || m.YOffset != 0 {
+		t.Fatalf("expected 1 line at y offset 0 after widening, got %d lines at %d", m.TotalLineCount(), m.YOffset)
+	}
+}
+
+// cmdMsgs runs the given command and returns the messages it produces,
+// running batched commands too.
+func cmdMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Slice {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for i := 0; i < v.Len(); i++ {
+		if c, ok := v.Index(i).Interface().(tea.Cmd); ok {
+			msgs = append(msgs, cmdMsgs(c)...)
+		}
+	}
+	return msgs
+}
+
+func TestReachedTopAndBottom(t *testing.T) {
+	m := newTestModel(20, 5)
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	up := tea.KeyMsg{Type: tea.KeyUp}
+
+	has := func(msgs []tea.Msg, msg tea.Msg) bool {
+		for _, m := range msgs {
+			if m == msg {
+				return true
+			}
+		}
+		return false
+	}
+
+	m.SetYOffset(14)
+	m, cmd := m.Update(down)
+	if !has(cmdMsgs(cmd), ReachedBottomMsg{}) {
+		t.Fatal("expected ReachedBottomMsg when scrolling to the bottom")
+	}
+	m, cmd = m.Update(down)
+	if !has(cmdMsgs(cmd), ReachedBottomMsg{}) {
+		t.Fatal("expected ReachedBottomMsg when scrolling down at the bottom")
+	}
+	m, cmd = m.Update(up)
+	if msgs := cmdMsgs(cmd); len(msgs) != 0 {
+		t.Fatalf("expected no messages away from the boundaries, got %v", msgs)
+	}
+
+	m.SetYOffset(1)
+	m, cmd = m.Update(up)
+	if !has(cmdMsgs(cmd), ReachedTopMsg{}) {
+		t.Fatal("expected ReachedTopMsg when scrolling to the top")
+	}
+	_, cmd = m.Update(up)
+	if !has(cmdMsgs(cmd), ReachedTopMsg{}) {
+		t.Fatal("expected ReachedTopMsg when scrolling up at the top")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	m := newTestModel(20, 5)
+	m.SetContent("foo\nbar\nbaz foo\nqux\nquux\ncorge\ngrault\nfoo")
+
+	m.Search("nope")
+	if m.NextMatch() || m.PrevMatch() {
+		t.Fatal("expected no match")
+	}
+
+	m.Search("foo")
+	for _, expected := range []struct{ line, col int }{{0, 0}, {2, 4}, {7, 0}, {0, 0}} {
+		if !m.NextMatch() || m.matchLine != expected.line || m.matchCol != expected.col {
+			t.Fatalf("expected next match at %v, got %d:%d", expected, m.matchLine, m.matchCol)
+		}
+	}
+	if m.YOffset != 0 {
+		t.Fatalf("expected to wrap around to the top, got y offset %d", m.YOffset)
+	}
+
+	for _, expected := range []struct{ line, col int }{{7, 0}, {2, 4}, {0, 0}} {
+		if !m.PrevMatch() || m.matchLine != expected.line || m.matchCol != expected.col {
+			t.Fatalf("expected previous match at %v, got %d:%d", expected, m.matchLine, m.matchCol)
+		}
+	}
+}
+
+func TestSearchStyledContent(t *testing.T) {
+	m := New(20, 2)
+	m.SearchMatchStyle = lipgloss.NewStyle().Underline(true)
+	m.CurrentMatchStyle = m.SearchMatchStyle
+	m.SetContent("a\x1b[31mfo\x1b[32mo\x1b[0mb \x1b[1m31m\x1b[0m")
+
+	// The term spans escape sequences and must not match inside them.
+	m.Search("foo")
+	if !m.NextMatch() || m.matchCol != 1 {
+		t.Fatalf("expected a match at column 1, got %d", m.matchCol)
+	}
+	expected := "a\x1b[31m" + m.SearchMatchStyle.Render("foo") + "\x1b[31m\x1b[32m\x1b[0mb \x1b[1m31m\x1b[0m"
+	if v := m.RenderLines(0, 1); v != expected {
+		t.Fatalf("expected the match to be highlighted and styling restored, got %q", v)
+	}
+
+	m.Search("[31m")
+	if m.NextMatch() {
+		t.Fatal("expected escape sequences not to be searched")
+	}
+}
+
+func TestStickyRegions(t *testing.T) {
+	m := newTestModel(20, 5)
+	m.SetStickyTop(1, "head")
+	m.SetStickyBottom(1, "foot\nignored")
+
+	if n := m.VisibleLineCount(); n != 3 {
+		t.Fatalf("expected 3 scrolling lines, got %d", n)
+	}
+
+	m.GotoBottom()
+	if m.YOffset != 17 {
+		t.Fatalf("expected y offset 17 at the bottom, got %d", m.YOffset)
+	}
+
+	lines := strings.Split(m.View(), "\n")
+	expected := []string{"head", "17", "18", "19", "foot"}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %q", len(expected), lines)
+	}
+	for i := range expected {
+		if strings.TrimRight(lines[i], " ") != expected[i] {
+			t.Fatalf("expected %q, got %q", expected, lines)
+		}
+	}
+}